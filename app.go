@@ -38,7 +38,17 @@ type Application struct {
 	terminate      func(status int) // See Terminate()
 	noInterspersed bool             // can flags be interspersed with args (or must they come first)
 	defaultEnvars  bool
+	envarFiles     bool
+	separator      string // Default separator for repeatable flags.
 	completion     bool
+	descriptions   bool // Output completions with descriptions.
+	configFiles    []string
+	configFlag     *FlagClause
+	configLoaders  map[string]ConfigLoader
+	dotEnvFiles    []string
+	dotEnv         map[string]string // Variables loaded from dotEnvFiles.
+	prompter       Prompter
+	promptMissing  bool // Prompt for all missing required values.
 
 	// Help flag. Exposed for user customisation.
 	HelpFlag *FlagClause
@@ -65,9 +75,12 @@ func New(name, help string) *Application {
 	a.HelpFlag.Bool()
 	a.Flag("help-long", "Generate long help.").Hidden().PreAction(a.generateLongHelp).Bool()
 	a.Flag("help-man", "Generate a man page.").Hidden().PreAction(a.generateManPage).Bool()
+	a.Flag("help-env", "Show environment variables.").Hidden().PreAction(a.generateEnvarHelp).Bool()
 	a.Flag("completion-bash", "Output possible completions for the given args.").Hidden().BoolVar(&a.completion)
+	a.Flag("completion-descriptions", "Output possible completions for the given args, with descriptions.").Hidden().BoolVar(&a.descriptions)
 	a.Flag("completion-script-bash", "Generate completion script for bash.").Hidden().PreAction(a.generateBashCompletionScript).Bool()
 	a.Flag("completion-script-zsh", "Generate completion script for ZSH.").Hidden().PreAction(a.generateZSHCompletionScript).Bool()
+	a.Flag("completion-script-fish", "Generate completion script for fish.").Hidden().PreAction(a.generateFishCompletionScript).Bool()
 
 	return a
 }
@@ -90,6 +103,15 @@ func (a *Application) generateManPage(c *ParseContext) error {
 	return nil
 }
 
+func (a *Application) generateEnvarHelp(c *ParseContext) error {
+	a.Writer(os.Stdout)
+	if err := a.UsageForContextWithTemplate(c, 2, EnvarHelpTemplate); err != nil {
+		return err
+	}
+	a.terminate(0)
+	return nil
+}
+
 func (a *Application) generateBashCompletionScript(c *ParseContext) error {
 	a.Writer(os.Stdout)
 	if err := a.UsageForContextWithTemplate(c, 2, BashCompletionTemplate); err != nil {
@@ -108,6 +130,15 @@ func (a *Application) generateZSHCompletionScript(c *ParseContext) error {
 	return nil
 }
 
+func (a *Application) generateFishCompletionScript(c *ParseContext) error {
+	a.Writer(os.Stdout)
+	if err := a.UsageForContextWithTemplate(c, 2, FishCompletionTemplate); err != nil {
+		return err
+	}
+	a.terminate(0)
+	return nil
+}
+
 // DefaultEnvars configures all flags (that do not already have an associated
 // envar) to use a default environment variable in the form "<app>_<flag>".
 //
@@ -118,6 +149,20 @@ func (a *Application) DefaultEnvars() *Application {
 	return a
 }
 
+// EnvarFiles allows the values of all envars to be read from files. See
+// FlagClause.EnvarFile().
+func (a *Application) EnvarFiles() *Application {
+	a.envarFiles = true
+	return a
+}
+
+// Separator sets the separator for all repeatable flags that don't set their
+// own. See FlagClause.Separator().
+func (a *Application) Separator(sep string) *Application {
+	a.separator = sep
+	return a
+}
+
 // Terminate specifies the termination handler. Defaults to os.Exit(status).
 // If nil is passed, a no-op function will be used.
 func (a *Application) Terminate(terminate func(int)) *Application {
@@ -206,11 +251,12 @@ func (a *Application) Parse(args []string) (command string, err error) {
 
 	selected, setValuesErr = a.setValues(context)
 
-	if err = a.applyPreActions(context, !a.completion); err != nil {
+	completion := a.completion || a.descriptions
+	if err = a.applyPreActions(context, !completion); err != nil {
 		return "", err
 	}
 
-	if a.completion {
+	if completion {
 		a.generateBashCompletion(context)
 		a.terminate(0)
 	} else {
@@ -355,10 +401,58 @@ func (a *Application) init() error {
 			return err
 		}
 	}
+	if err := checkDuplicateEnvars(&a.cmdMixin, nil); err != nil {
+		return err
+	}
+	if a.envarFiles {
+		a.eachEnvar(func(e *envarMixin) { e.envarFile = true })
+	}
+	if a.separator != "" {
+		a.eachFlag(func(f *FlagClause) {
+			if v, ok := f.value.(repeatableFlag); ok && v.IsCumulative() && f.separator == "" {
+				f.separator = a.separator
+			}
+		})
+	}
+	if err := a.loadDotEnv(); err != nil {
+		return err
+	}
 	a.initialized = true
 	return nil
 }
 
+// Call fn for the envars of all flags and arguments of the application and
+// its commands.
+func (a *Application) eachEnvar(fn func(*envarMixin)) {
+	var walk func(c *cmdMixin)
+	walk = func(c *cmdMixin) {
+		for _, flag := range c.flagOrder {
+			fn(&flag.envarMixin)
+		}
+		for _, arg := range c.args {
+			fn(&arg.envarMixin)
+		}
+		for _, cmd := range c.commandOrder {
+			walk(&cmd.cmdMixin)
+		}
+	}
+	walk(&a.cmdMixin)
+}
+
+// Call fn for all flags of the application and its commands.
+func (a *Application) eachFlag(fn func(*FlagClause)) {
+	var walk func(c *cmdMixin)
+	walk = func(c *cmdMixin) {
+		for _, flag := range c.flagOrder {
+			fn(flag)
+		}
+		for _, cmd := range c.commandOrder {
+			walk(&cmd.cmdMixin)
+		}
+	}
+	walk(&a.cmdMixin)
+}
+
 // Recursively check commands for duplicate flags.
 func checkDuplicateFlags(current *CmdClause, flagGroups []*flagGroup) error {
 	// Check for duplicates.
@@ -384,6 +478,42 @@ func checkDuplicateFlags(current *CmdClause, flagGroups []*flagGroup) error {
 	return nil
 }
 
+// Recursively check that no two flags or arguments that can be used together
+// share an envar. Sibling commands are never active together, so each one
+// starts from a copy of the envars seen in its parents.
+func checkDuplicateEnvars(c *cmdMixin, parents map[string]string) error {
+	seen := map[string]string{}
+	for envar, owner := range parents {
+		seen[envar] = owner
+	}
+	claim := func(envar, owner string) error {
+		if envar == "" {
+			return nil
+		}
+		if other, ok := seen[envar]; ok {
+			return fmt.Errorf("%s and %s both use envar $%s", other, owner, envar)
+		}
+		seen[envar] = owner
+		return nil
+	}
+	for _, flag := range c.flagOrder {
+		if err := claim(flag.envar, "flag --"+flag.name); err != nil {
+			return err
+		}
+	}
+	for _, arg := range c.args {
+		if err := claim(arg.envar, "argument '"+arg.name+"'"); err != nil {
+			return err
+		}
+	}
+	for _, cmd := range c.commandOrder {
+		if err := checkDuplicateEnvars(&cmd.cmdMixin, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *Application) execute(context *ParseContext, selected []string) (string, error) {
 	var err error
 
@@ -428,10 +558,14 @@ func (a *Application) setDefaults(context *ParseContext) error {
 		}
 	}
 
+	if err := a.loadConfig(context); err != nil {
+		return err
+	}
+
 	// Check required flags and set defaults.
 	for _, flag := range context.flags.long {
 		if flagElements[flag.name] == nil {
-			if err := flag.setDefault(); err != nil {
+			if err := flag.setDefault(context); err != nil {
 				return err
 			}
 		}
@@ -439,7 +573,7 @@ func (a *Application) setDefaults(context *ParseContext) error {
 
 	for _, arg := range context.arguments.args {
 		if argElements[arg.name] == nil {
-			if err := arg.setDefault(); err != nil {
+			if err := arg.setDefault(context); err != nil {
 				return err
 			}
 		}
@@ -463,14 +597,17 @@ func (a *Application) validateRequired(context *ParseContext) error {
 		}
 	}
 
-	// Check required flags and set defaults.
+	// Check required flags were provided, prompting for them if possible.
 	var missingFlags []string
-	for _, flag := range context.flags.long {
-		if flagElements[flag.name] == nil {
-			// Check required flags were provided.
-			if flag.needsValue() {
-				missingFlags = append(missingFlags, fmt.Sprintf("'--%s'", flag.name))
+	for _, flag := range context.flags.flagOrder {
+		if flagElements[flag.name] == nil && flag.needsValue(context) {
+			if ok, err := a.promptValue(flag.value, flag.setter(), flag.prompt, flag.help, flag.name, flag.secret); err != nil {
+				return fmt.Errorf("invalid value for flag '--%s': %s", flag.name, err)
+			} else if ok {
+				flag.source = ValueSource{Kind: SourcePrompt}
+				continue
 			}
+			missingFlags = append(missingFlags, fmt.Sprintf("'--%s'", flag.name))
 		}
 	}
 	if len(missingFlags) != 0 {
@@ -478,10 +615,14 @@ func (a *Application) validateRequired(context *ParseContext) error {
 	}
 
 	for _, arg := range context.arguments.args {
-		if argElements[arg.name] == nil {
-			if arg.needsValue() {
-				return fmt.Errorf("required argument '%s' not provided", arg.name)
+		if argElements[arg.name] == nil && arg.needsValue(context) {
+			if ok, err := a.promptValue(arg.value, arg.value, arg.prompt, arg.help, arg.name, false); err != nil {
+				return fmt.Errorf("invalid value for argument '%s': %s", arg.name, err)
+			} else if ok {
+				arg.source = ValueSource{Kind: SourcePrompt}
+				continue
 			}
+			return fmt.Errorf("required argument '%s' not provided", arg.name)
 		}
 	}
 	return nil
@@ -501,15 +642,25 @@ func (a *Application) setValues(context *ParseContext) (selected []string, err e
 					return nil, fmt.Errorf("flag '%s' cannot be repeated", clause.name)
 				}
 			}
-			if err = clause.value.Set(*element.Value); err != nil {
+			var value string
+			if value, err = context.readFromFile(*element.Value, clause.fromFilePrefix); err != nil {
+				return nil, fmt.Errorf("flag '%s': %s", clause.name, err)
+			}
+			if err = clause.setter().Set(value); err != nil {
 				return
 			}
+			clause.source = ValueSource{Kind: SourceCommandLine, Index: element.index}
 			flagSet[clause.name] = struct{}{}
 
 		case *ArgClause:
-			if err = clause.value.Set(*element.Value); err != nil {
+			var value string
+			if value, err = context.readFromFile(*element.Value, clause.fromFilePrefix); err != nil {
+				return nil, fmt.Errorf("argument '%s': %s", clause.name, err)
+			}
+			if err = clause.value.Set(value); err != nil {
 				return
 			}
+			clause.source = ValueSource{Kind: SourceCommandLine, Index: element.index}
 
 		case *CmdClause:
 			selected = append(selected, clause.name)
@@ -618,6 +769,13 @@ func (a *Application) FatalIfError(err error, format string, args ...interface{}
 }
 
 func (a *Application) completionOptions(context *ParseContext) []string {
+	options, _ := a.completionCandidates(context)
+	return options
+}
+
+// Completion options for the command line, and the completions of the flag or
+// argument being completed, if any.
+func (a *Application) completionCandidates(context *ParseContext) ([]string, *completionsMixin) {
 	args := context.rawArgs
 
 	var (
@@ -643,7 +801,7 @@ func (a *Application) completionOptions(context *ParseContext) []string {
 
 	if (currArg != "" && strings.HasPrefix(currArg, "--")) || strings.HasPrefix(prevArg, "--") {
 		if context.argsOnly {
-			return nil
+			return nil, nil
 		}
 
 		// Perform completion for A flag. The last/current argument started with "-"
@@ -664,37 +822,40 @@ func (a *Application) completionOptions(context *ParseContext) []string {
 			flagName = currArg[2:] // Strip the "--"
 		}
 
-		options, flagMatched, valueMatched := target.FlagCompletion(flagName, flagValue)
+		options, completing, flagMatched, valueMatched := target.flagCompletion(flagName, flagValue)
 		if valueMatched {
 			// Value Matched. Show cmdCompletions
-			return target.CmdCompletion(context)
+			return target.cmdCompletion(context)
 		}
 
 		// Add top level flags if we're not at the top level and no match was found.
 		if context.SelectedCommand != nil && !flagMatched {
-			topOptions, topFlagMatched, topValueMatched := a.FlagCompletion(flagName, flagValue)
+			topOptions, topCompleting, topFlagMatched, topValueMatched := a.flagCompletion(flagName, flagValue)
 			if topValueMatched {
 				// Value Matched. Back to cmdCompletions
-				return target.CmdCompletion(context)
+				return target.cmdCompletion(context)
 			}
 
 			if topFlagMatched {
 				// Top level had a flag which matched the input. Return it's options.
-				options = topOptions
+				options, completing = topOptions, topCompleting
 			} else {
 				// Add top level flags
 				options = append(options, topOptions...)
 			}
 		}
-		return options
+		return options, completing
 	}
 
 	// Perform completion for sub commands and arguments.
-	return target.CmdCompletion(context)
+	return target.cmdCompletion(context)
 }
 
 func (a *Application) generateBashCompletion(context *ParseContext) {
-	options := a.completionOptions(context)
+	options, completing := a.completionCandidates(context)
+	if a.descriptions && completing != nil {
+		options = completing.describeCompletions(options)
+	}
 	fmt.Printf("%s", strings.Join(options, "\n"))
 }
 
@@ -228,6 +228,57 @@ func TestDefaultEnvars(t *testing.T) {
 	assert.Equal(t, "SOME_APP_A_1_FLAG", f2.envar)
 }
 
+func TestCommandDefaultEnvars(t *testing.T) {
+	a := New("some-app", "").Terminate(nil).DefaultEnvars()
+	f0 := a.Flag("some-flag", "")
+	f0.Bool()
+	db := a.Command("db", "").DefaultEnvars()
+	migrate := db.Command("migrate", "")
+	f1 := migrate.Flag("dry-run", "")
+	f1.Bool()
+	cache := a.Command("cache", "")
+	f2 := cache.Flag("size", "")
+	f2.Int()
+	_, err := a.Parse([]string{"db", "migrate"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SOME_APP_SOME_FLAG", f0.envar)
+	assert.Equal(t, "SOME_APP_DB_MIGRATE_DRY_RUN", f1.envar)
+	assert.Equal(t, "SOME_APP_SIZE", f2.envar)
+}
+
+func TestDuplicateEnvars(t *testing.T) {
+	a := New("app", "").Terminate(nil).DefaultEnvars()
+	a.Flag("db-host", "").String()
+	a.Command("db", "").DefaultEnvars().Flag("host", "").String()
+	_, err := a.Parse([]string{"db"})
+	assert.EqualError(t, err, "flag --db-host and flag --host both use envar $APP_DB_HOST")
+
+	a = newTestApp()
+	a.Flag("a", "").Envar("SAME").String()
+	a.Flag("b", "").Envar("SAME").String()
+	_, err = a.Parse([]string{})
+	assert.EqualError(t, err, "flag --a and flag --b both use envar $SAME")
+
+	a = newTestApp()
+	a.Arg("a", "").Envar("SAME").String()
+	a.Arg("b", "").Envar("SAME").String()
+	_, err = a.Parse([]string{})
+	assert.EqualError(t, err, "argument 'a' and argument 'b' both use envar $SAME")
+
+	a = New("app", "").Terminate(nil).DefaultEnvars()
+	a.Flag("name", "").String()
+	a.Command("greet", "").Arg("name", "").Envar("APP_NAME").String()
+	_, err = a.Parse([]string{"greet"})
+	assert.EqualError(t, err, "flag --name and argument 'name' both use envar $APP_NAME")
+
+	// Flags on sibling commands are never active together.
+	a = newTestApp().DefaultEnvars()
+	a.Command("one", "").Flag("name", "").String()
+	a.Command("two", "").Flag("name", "").String()
+	_, err = a.Parse([]string{"one"})
+	assert.NoError(t, err)
+}
+
 func TestBashCompletionOptionsWithEmptyApp(t *testing.T) {
 	a := newTestApp()
 	context, err := a.ParseContext([]string{"--completion-bash"})
@@ -2,6 +2,7 @@ package kingpin
 
 import (
 	"fmt"
+	"path/filepath"
 )
 
 type argGroup struct {
@@ -71,9 +72,17 @@ type ArgClause struct {
 	name          string
 	help          string
 	defaultValues []string
+	defaultFunc   func() (string, error)
+	defaultHelp   string // Shown in help instead of the default values.
+	expandDefault bool   // Expand ${VAR} and ~ in default values.
 	placeholder   string
 	hidden        bool
 	required      bool
+	prompt        string
+	ignoreCase    bool
+	// Prefix of values to read from files, set by values such as JSON().
+	fromFilePrefix string
+	source         ValueSource
 }
 
 func newArg(name, help string) *ArgClause {
@@ -84,35 +93,38 @@ func newArg(name, help string) *ArgClause {
 	return a
 }
 
-func (a *ArgClause) setDefault() error {
-	if a.HasEnvarValue() {
-		if v, ok := a.value.(remainderArg); !ok || !v.IsCumulative() {
-			// Use the value as-is
-			return a.value.Set(a.GetEnvarValue())
-		}
-		for _, value := range a.GetSplitEnvarValue() {
-			if err := a.value.Set(value); err != nil {
-				return err
-			}
-		}
-		return nil
+func (a *ArgClause) setDefault(context *ParseContext) error {
+	if name := a.envarName(); name != "" {
+		a.source = ValueSource{Kind: SourceEnvar, Envar: name}
+		return a.setEnvarValue(a.value)
+	}
+
+	if config, ok := context.config[a]; ok {
+		a.source = ValueSource{Kind: SourceConfig, ConfigFile: context.configFile, ConfigKey: config.key}
+		defer withBaseDir(a.value, filepath.Dir(context.configFile))()
+		return context.applyConfig(config, a.value)
 	}
 
-	if len(a.defaultValues) > 0 {
-		for _, defaultValue := range a.defaultValues {
-			if err := a.value.Set(defaultValue); err != nil {
-				return err
-			}
+	if len(a.defaultValues) > 0 || a.defaultFunc != nil {
+		a.source = ValueSource{Kind: SourceDefault}
+		var getenv func(string) string
+		if a.expandDefault {
+			getenv = a.getenv
+		}
+		if err := setDefaultValues(a.value, a.defaultValues, a.defaultFunc, getenv); err != nil {
+			return fmt.Errorf("default for argument '%s': %s", a.name, err)
 		}
 		return nil
 	}
 
+	a.source = ValueSource{}
 	return nil
 }
 
-func (a *ArgClause) needsValue() bool {
-	haveDefault := len(a.defaultValues) > 0
-	return a.required && !(haveDefault || a.HasEnvarValue())
+func (a *ArgClause) needsValue(context *ParseContext) bool {
+	haveDefault := len(a.defaultValues) > 0 || a.defaultFunc != nil
+	_, haveConfig := context.config[a]
+	return a.required && !(haveDefault || haveConfig || a.HasEnvarValue())
 }
 
 func (a *ArgClause) consumesRemainder() bool {
@@ -141,12 +153,38 @@ func (a *ArgClause) Required() *ArgClause {
 	return a
 }
 
+// Source returns where the value of the argument came from during the most
+// recent parse.
+func (a *ArgClause) Source() ValueSource {
+	return a.source
+}
+
 // Default values for this argument. They *must* be parseable by the value of the argument.
 func (a *ArgClause) Default(values ...string) *ArgClause {
 	a.defaultValues = values
 	return a
 }
 
+// ExpandDefault expands ${VAR} references and a leading ~ in the default
+// values of the argument when they are used. "\${" is not expanded.
+func (a *ArgClause) ExpandDefault() *ArgClause {
+	a.expandDefault = true
+	return a
+}
+
+// DefaultFunc computes the default value of the argument with fn, which is
+// only called if the argument is not otherwise set.
+func (a *ArgClause) DefaultFunc(fn func() (string, error)) *ArgClause {
+	a.defaultFunc = fn
+	return a
+}
+
+// DefaultHelp describes the default value in help instead of showing it.
+func (a *ArgClause) DefaultHelp(help string) *ArgClause {
+	a.defaultHelp = help
+	return a
+}
+
 // Envar overrides the default value(s) for a flag from an environment variable,
 // if it is set. Several default values can be provided by using new lines to
 // separate them.
@@ -156,6 +194,20 @@ func (a *ArgClause) Envar(name string) *ArgClause {
 	return a
 }
 
+// EnvarFile allows the value of the argument's envar to be read from the file
+// named by "<envar>_FILE". See FlagClause.EnvarFile().
+func (a *ArgClause) EnvarFile() *ArgClause {
+	a.envarFile = true
+	return a
+}
+
+// EnvarSeparator sets the separator between multiple values in the
+// argument's envar. See FlagClause.EnvarSeparator().
+func (a *ArgClause) EnvarSeparator(sep string) *ArgClause {
+	a.envarSeparator = sep
+	return a
+}
+
 // NoEnvar forces environment variable defaults to be disabled for this flag.
 // Most useful in conjunction with app.DefaultEnvars().
 func (a *ArgClause) NoEnvar() *ArgClause {
@@ -188,6 +240,13 @@ func (a *ArgClause) HintOptions(options ...string) *ArgClause {
 	return a
 }
 
+// IgnoreCase matches enum options case-insensitively. The value is set to the
+// option as it was defined.
+func (a *ArgClause) IgnoreCase() *ArgClause {
+	a.ignoreCase = true
+	return a
+}
+
 // Help sets the help message.
 func (a *ArgClause) Help(help string) *ArgClause {
 	a.help = help
@@ -195,11 +254,25 @@ func (a *ArgClause) Help(help string) *ArgClause {
 }
 
 func (a *ArgClause) init() error {
-	if a.required && len(a.defaultValues) > 0 {
+	if a.required && (len(a.defaultValues) > 0 || a.defaultFunc != nil) {
 		return fmt.Errorf("required argument '%s' with unusable default value", a.name)
 	}
 	if a.value == nil {
 		return fmt.Errorf("no parser defined for arg '%s'", a.name)
 	}
+	if v, ok := a.value.(enumOptionsValue); ok {
+		if a.ignoreCase {
+			v.setIgnoreCase()
+		}
+		if len(a.builtinHintActions) == 0 {
+			a.addHintActionBuiltin(func() []string { return v.getEnumOptions().options })
+			a.describeBuiltin = v.getEnumOptions().describe
+		}
+	} else if a.ignoreCase {
+		return fmt.Errorf("IgnoreCase() for arg '%s', which is not an enum", a.name)
+	}
+	if v, ok := a.value.(fromFileValue); ok {
+		a.fromFilePrefix = v.fromFilePrefix()
+	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package kingpin
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -82,3 +83,30 @@ func TestSubcommandArgRequiredWithEnvar(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 123, *flag)
 }
+
+func TestArgDefaultFunc(t *testing.T) {
+	app := newTestApp()
+	a := app.Arg("a", "").DefaultFunc(func() (string, error) { return "computed", nil }).String()
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "computed", *a)
+	_, err = app.Parse([]string{"given"})
+	assert.NoError(t, err)
+	assert.Equal(t, "given", *a)
+
+	app = newTestApp()
+	app.Arg("a", "").DefaultFunc(func() (string, error) { return "", fmt.Errorf("boom") }).String()
+	_, err = app.Parse([]string{})
+	assert.EqualError(t, err, "default for argument 'a': boom")
+}
+
+func TestArgDefaultLiteral(t *testing.T) {
+	t.Setenv("TEST_AMOUNT", "10")
+	app := newTestApp()
+	a := app.Arg("a", "").Default("price: ${TEST_AMOUNT}").String()
+	b := app.Arg("b", "").Default("${TEST_AMOUNT}").ExpandDefault().String()
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "price: ${TEST_AMOUNT}", *a)
+	assert.Equal(t, "10", *b)
+}
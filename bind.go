@@ -0,0 +1,303 @@
+package kingpin
+
+import (
+	"encoding"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/alecthomas/units"
+)
+
+// Implemented by Application and CmdClause.
+type clauseFactory interface {
+	Flag(name, help string) *FlagClause
+	Arg(name, help string) *ArgClause
+	Command(name, help string) *CmdClause
+}
+
+// Bind defines flags, arguments and commands from the fields of the struct
+// pointed to by cfg. Parsed values are stored directly in the fields.
+//
+// Fields are bound according to their struct tags:
+//
+//	flag:"name"       a flag, named after the field if the name is empty
+//	arg:"name"        a positional argument, in field order
+//	cmd:"name"        a command, the field being a struct bound recursively
+//	short:"n"         short flag name
+//	help:"..."        help text
+//	default:"..."     default value, comma-separated for slice fields
+//	envar:"NAME"      environment variable
+//	enum:"a,b,c"      restrict a string or []string field to options
+//	placeholder:"X"   placeholder in help
+//	required:""       the flag or argument is required
+//	hidden:""         the flag, argument or command is hidden
+//	secret:""         the flag is a Secret()
+//
+// Untagged fields are ignored, except for embedded structs whose fields are
+// bound as if they were declared in the outer struct.
+//
+// Fields may be of any type that kingpin can parse, a slice of such a type
+// for repeatable flags and arguments, or a type whose pointer implements
+// Value or encoding.TextUnmarshaler.
+func Bind(app *Application, cfg interface{}) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected a pointer to a struct but got %T", cfg)
+	}
+	return bindStruct(app, v.Elem())
+}
+
+func bindStruct(factory clauseFactory, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+		tag := field.Tag
+		flagName, isFlag := tag.Lookup("flag")
+		argName, isArg := tag.Lookup("arg")
+		cmdName, isCmd := tag.Lookup("cmd")
+		if field.PkgPath != "" && (isFlag || isArg || isCmd) {
+			return fmt.Errorf("field %s: can't bind unexported field", field.Name)
+		}
+		switch {
+		case isFlag:
+			target, err := bindTarget(field, value)
+			if err != nil {
+				return err
+			}
+			flag := factory.Flag(bindName(flagName, field.Name), tag.Get("help"))
+			if short := tag.Get("short"); short != "" {
+				flag.Short([]rune(short)[0])
+			}
+			if defaults := bindDefaults(field); len(defaults) > 0 {
+				flag.Default(defaults...)
+			}
+			if envar := tag.Get("envar"); envar != "" {
+				flag.Envar(envar)
+			}
+			if placeholder := tag.Get("placeholder"); placeholder != "" {
+				flag.PlaceHolder(placeholder)
+			}
+			if _, ok := tag.Lookup("required"); ok {
+				flag.Required()
+			}
+			if _, ok := tag.Lookup("hidden"); ok {
+				flag.Hidden()
+			}
+			if _, ok := tag.Lookup("secret"); ok {
+				flag.Secret()
+			}
+			flag.SetValue(target)
+
+		case isArg:
+			target, err := bindTarget(field, value)
+			if err != nil {
+				return err
+			}
+			arg := factory.Arg(bindName(argName, field.Name), tag.Get("help"))
+			if defaults := bindDefaults(field); len(defaults) > 0 {
+				arg.Default(defaults...)
+			}
+			if envar := tag.Get("envar"); envar != "" {
+				arg.Envar(envar)
+			}
+			if placeholder := tag.Get("placeholder"); placeholder != "" {
+				arg.PlaceHolder(placeholder)
+			}
+			if _, ok := tag.Lookup("required"); ok {
+				arg.Required()
+			}
+			if _, ok := tag.Lookup("hidden"); ok {
+				arg.Hidden()
+			}
+			arg.SetValue(target)
+
+		case isCmd:
+			if value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Struct {
+				if value.IsNil() {
+					value.Set(reflect.New(value.Type().Elem()))
+				}
+				value = value.Elem()
+			}
+			if value.Kind() != reflect.Struct {
+				return fmt.Errorf("field %s: commands must be structs but got %s", field.Name, field.Type)
+			}
+			cmd := factory.Command(bindName(cmdName, field.Name), tag.Get("help"))
+			if _, ok := tag.Lookup("hidden"); ok {
+				cmd.Hidden()
+			}
+			if err := bindStruct(cmd, value); err != nil {
+				return err
+			}
+
+		case field.Anonymous && value.Kind() == reflect.Struct:
+			if err := bindStruct(factory, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Create a Value storing into a struct field.
+func bindTarget(field reflect.StructField, value reflect.Value) (Value, error) {
+	if enum, ok := field.Tag.Lookup("enum"); ok {
+		options := strings.Split(enum, ",")
+		switch target := value.Addr().Interface().(type) {
+		case *string:
+			return newEnumFlag(target, options...), nil
+		case *[]string:
+			return newEnumsFlag(target, options...), nil
+		}
+		return nil, fmt.Errorf("field %s: enums must be string or []string but got %s", field.Name, field.Type)
+	}
+	target := valueForPointer(value.Addr().Interface())
+	if target == nil {
+		return nil, fmt.Errorf("field %s: unsupported type %s", field.Name, field.Type)
+	}
+	return target, nil
+}
+
+// Return a Value storing into target, which must be a pointer, or nil if its
+// type is not supported.
+func valueForPointer(target interface{}) Value {
+	switch target := target.(type) {
+	case *string:
+		return newStringValue(target)
+	case *bool:
+		return newBoolValue(target)
+	case *int:
+		return newIntValue(target)
+	case *int8:
+		return newInt8Value(target)
+	case *int16:
+		return newInt16Value(target)
+	case *int32:
+		return newInt32Value(target)
+	case *int64:
+		return newInt64Value(target)
+	case *uint:
+		return newUintValue(target)
+	case *uint8:
+		return newUint8Value(target)
+	case *uint16:
+		return newUint16Value(target)
+	case *uint32:
+		return newUint32Value(target)
+	case *uint64:
+		return newUint64Value(target)
+	case *float32:
+		return newFloat32Value(target)
+	case *float64:
+		return newFloat64Value(target)
+	case *time.Duration:
+		return newDurationValue(target)
+	case *time.Time:
+		return newTimeValue(target)
+	case **time.Location:
+		return newTimeZoneValue(target)
+	case *units.Base2Bytes:
+		return newBytesValue(target)
+	case *units.MetricBytes:
+		return newMetricBytesValue(target)
+	case *net.IP:
+		return newIPValue(target)
+	case **net.TCPAddr:
+		return newTCPAddrValue(target)
+	case **net.UDPAddr:
+		return newUDPValue(target)
+	case **net.IPNet:
+		return newIPNetValue(target)
+	case *net.HardwareAddr:
+		return newHardwareAddrValue(target)
+	case *netip.Addr:
+		return newAddrValue(target)
+	case *netip.AddrPort:
+		return newAddrPortValue(target)
+	case *netip.Prefix:
+		return newPrefixValue(target)
+	case *PortRange:
+		return newPortRangeValue(target)
+	case **url.URL:
+		return newURLValue(target)
+	case **regexp.Regexp:
+		return newRegexpValue(target)
+	case *[]byte:
+		return newHexBytesValue(target)
+	case *map[string]string:
+		if *target == nil {
+			*target = map[string]string{}
+		}
+		return newStringMapValue(target)
+	case Value:
+		return target
+	case Text:
+		return &wrapText{target}
+	case encoding.TextUnmarshaler:
+		return &unmarshalerValue{target}
+	}
+	t := reflect.TypeOf(target)
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Slice {
+		if valueForPointer(reflect.New(t.Elem().Elem()).Interface()) == nil {
+			return nil
+		}
+		return newAccumulator(target, valueForPointer)
+	}
+	return nil
+}
+
+// Adapts an encoding.TextUnmarshaler that doesn't implement
+// encoding.TextMarshaler.
+type unmarshalerValue struct {
+	target encoding.TextUnmarshaler
+}
+
+func (u *unmarshalerValue) Set(value string) error {
+	return u.target.UnmarshalText([]byte(value))
+}
+
+func (u *unmarshalerValue) String() string {
+	if s, ok := u.target.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return ""
+}
+
+func bindDefaults(field reflect.StructField) []string {
+	value, ok := field.Tag.Lookup("default")
+	if !ok {
+		return nil
+	}
+	if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8 {
+		return splitList(value, ",")
+	}
+	return []string{value}
+}
+
+// Use name if set, otherwise convert a field name such as HTTPPort to
+// http-port.
+func bindName(name, field string) string {
+	if name != "" {
+		return name
+	}
+	runes := []rune(field)
+	out := []rune{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				out = append(out, '-')
+			}
+		}
+		out = append(out, unicode.ToLower(r))
+	}
+	return string(out)
+}
@@ -0,0 +1,82 @@
+package kingpin
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bindCommon struct {
+	Verbose bool `flag:"" short:"v" help:"Verbose mode."`
+}
+
+type bindConfig struct {
+	bindCommon
+	Timeout  time.Duration     `flag:"" default:"5s" help:"Timeout."`
+	HTTPPort int               `flag:"" envar:"TEST_BIND_PORT"`
+	Tags     []string          `flag:"tag" default:"a,b"`
+	Level    string            `flag:"" enum:"debug,info" default:"info"`
+	Labels   map[string]string `flag:""`
+	Addr     net.IP            `flag:""`
+	ignored  string
+	Migrate  struct {
+		DryRun bool   `flag:""`
+		Target string `arg:"" required:""`
+	} `cmd:"" help:"Migrate the database."`
+	Status *struct{} `cmd:""`
+}
+
+func TestBind(t *testing.T) {
+	t.Setenv("TEST_BIND_PORT", "8080")
+	var cfg bindConfig
+	app := newTestApp()
+	assert.NoError(t, Bind(app, &cfg))
+	selected, err := app.Parse([]string{"-v", "--tag=x", "--tag=y", "--labels=a=b", "--addr=127.0.0.1", "migrate", "--dry-run", "head"})
+	assert.NoError(t, err)
+	assert.Equal(t, "migrate", selected)
+	assert.True(t, cfg.Verbose)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, 8080, cfg.HTTPPort)
+	assert.Equal(t, []string{"x", "y"}, cfg.Tags)
+	assert.Equal(t, "info", cfg.Level)
+	assert.Equal(t, map[string]string{"a": "b"}, cfg.Labels)
+	assert.Equal(t, "127.0.0.1", cfg.Addr.String())
+	assert.True(t, cfg.Migrate.DryRun)
+	assert.Equal(t, "head", cfg.Migrate.Target)
+	assert.NotNil(t, cfg.Status)
+	assert.NotNil(t, app.GetFlag("http-port"))
+	assert.Equal(t, "Migrate the database.", app.GetCommand("migrate").help)
+
+	_, err = app.Parse([]string{"--level=trace", "status"})
+	assert.Error(t, err)
+}
+
+func TestBindDefaults(t *testing.T) {
+	var cfg bindConfig
+	app := newTestApp()
+	assert.NoError(t, Bind(app, &cfg))
+	_, err := app.Parse([]string{"status"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, cfg.Tags)
+}
+
+func TestBindErrors(t *testing.T) {
+	assert.EqualError(t, Bind(newTestApp(), bindConfig{}), "expected a pointer to a struct but got kingpin.bindConfig")
+	var unsupported struct {
+		Ch chan int `flag:""`
+	}
+	assert.EqualError(t, Bind(newTestApp(), &unsupported), "field Ch: unsupported type chan int")
+	var enum struct {
+		N int `flag:"" enum:"1,2"`
+	}
+	assert.EqualError(t, Bind(newTestApp(), &enum), "field N: enums must be string or []string but got int")
+}
+
+func TestBindName(t *testing.T) {
+	assert.Equal(t, "http-port", bindName("", "HTTPPort"))
+	assert.Equal(t, "dry-run", bindName("", "DryRun"))
+	assert.Equal(t, "url", bindName("", "URL"))
+	assert.Equal(t, "explicit", bindName("explicit", "Field"))
+}
@@ -15,7 +15,17 @@ type cmdMixin struct {
 // CmdCompletion returns completion options for arguments, if that's where
 // parsing left off, or commands if there aren't any unsatisfied args.
 func (c *cmdMixin) CmdCompletion(context *ParseContext) []string {
-	var options []string
+	options, _ := c.cmdCompletion(context)
+	return options
+}
+
+// Like CmdCompletion, also returning the completions of the argument being
+// completed, if any.
+func (c *cmdMixin) cmdCompletion(context *ParseContext) ([]string, *completionsMixin) {
+	var (
+		options []string
+		target  *completionsMixin
+	)
 
 	// Count args already satisfied - we won't complete those, and add any
 	// default commands' alternatives, since they weren't listed explicitly
@@ -29,6 +39,7 @@ ElementLoop:
 			// Each new element should reset the previous state
 			allSatisfied = false
 			options = nil
+			target = nil
 
 			if el.Value != nil && *el.Value != "" {
 				// Get the list of valid options for the last argument
@@ -55,6 +66,7 @@ ElementLoop:
 					if strings.HasPrefix(opt, *el.Value) {
 						// If the option match the partially entered argument, add it to the list
 						options = append(options, opt)
+						target = &c.argGroup.args[argsSatisfied].completionsMixin
 					}
 				}
 				// Avoid further completion as we have done everything we could
@@ -71,7 +83,8 @@ ElementLoop:
 
 	if argsSatisfied < len(c.argGroup.args) && !allSatisfied {
 		// Since not all args have been satisfied, show options for the current one
-		options = append(options, c.argGroup.args[argsSatisfied].resolveCompletions()...)
+		target = &c.argGroup.args[argsSatisfied].completionsMixin
+		options = append(options, target.resolveCompletions()...)
 	} else {
 		// If all args are satisfied, then go back to completing commands
 		for _, cmd := range c.cmdGroup.commandOrder {
@@ -81,10 +94,17 @@ ElementLoop:
 		}
 	}
 
-	return options
+	return options, target
 }
 
 func (c *cmdMixin) FlagCompletion(flagName string, flagValue string) (choices []string, flagMatch bool, optionMatch bool) {
+	choices, _, flagMatch, optionMatch = c.flagCompletion(flagName, flagValue)
+	return
+}
+
+// Like FlagCompletion, also returning the completions of the flag being
+// completed, if any.
+func (c *cmdMixin) flagCompletion(flagName string, flagValue string) (choices []string, target *completionsMixin, flagMatch bool, optionMatch bool) {
 	// Check if flagName matches a known flag.
 	// If it does, show the options for the flag
 	// Otherwise, show all flags
@@ -98,7 +118,7 @@ func (c *cmdMixin) FlagCompletion(flagName string, flagValue string) (choices []
 			options = flag.resolveCompletions()
 			if len(options) == 0 {
 				// No Options to Choose From, Assume Match.
-				return options, true, true
+				return options, &flag.completionsMixin, true, true
 			}
 
 			// Loop options to find if the user specified value matches
@@ -115,7 +135,7 @@ func (c *cmdMixin) FlagCompletion(flagName string, flagValue string) (choices []
 
 			// Matched Flag Directly
 			// Flag Value Not Prefixed, and Matched Directly
-			return options, true, !isPrefix && matched
+			return options, &flag.completionsMixin, true, !isPrefix && matched
 		}
 
 		if !flag.hidden {
@@ -123,7 +143,7 @@ func (c *cmdMixin) FlagCompletion(flagName string, flagValue string) (choices []
 		}
 	}
 	// No Flag directly matched.
-	return options, false, false
+	return options, nil, false, false
 
 }
 
@@ -231,6 +251,7 @@ type CmdClause struct {
 	isDefault      bool
 	validator      CmdClauseValidator
 	hidden         bool
+	defaultEnvars  bool
 	completionAlts []string
 }
 
@@ -295,8 +316,29 @@ func (c *CmdClause) Help(help string) *CmdClause {
 	return c
 }
 
+// DefaultEnvars configures all flags of this command and its subcommands
+// (that do not already have an associated envar) to use a default environment
+// variable in the form "<app>_<command>_<flag>".
+//
+// For example, if the application is named "foo", the command is "db migrate"
+// and a flag is named "dry-run" the environment variable:
+// "FOO_DB_MIGRATE_DRY_RUN".
+func (c *CmdClause) DefaultEnvars() *CmdClause {
+	c.defaultEnvars = true
+	return c
+}
+
+func (c *CmdClause) defaultEnvarPrefix() string {
+	for p := c; p != nil; p = p.parent {
+		if p.defaultEnvars {
+			return c.app.Name + "_" + c.FullCommand()
+		}
+	}
+	return c.app.defaultEnvarPrefix()
+}
+
 func (c *CmdClause) init() error {
-	if err := c.flagGroup.init(c.app.defaultEnvarPrefix()); err != nil {
+	if err := c.flagGroup.init(c.defaultEnvarPrefix()); err != nil {
 		return err
 	}
 	if c.argGroup.have() && c.cmdGroup.have() {
@@ -1,19 +1,57 @@
+// Command genvalues generates kingpin Value types from a JSON or YAML spec.
+//
+// With no arguments it regenerates values_generated.go in the kingpin package
+// from values.json. To generate values for your own types:
+//
+//	//go:generate go run github.com/alecthomas/kingpin/v2/cmd/genvalues -spec values.yaml -package main -o values_generated.go
+//
+// The spec is a list of values, or an object with "imports" and "values":
+//
+//	imports: [net/mail]
+//	values:
+//	  - name: Email
+//	    type: "*mail.Address"
+//	    parser: mail.ParseAddress(s)
+//	    plural: Emails
+//
+// Outside the kingpin package each value gets Email(s kingpin.Settings),
+// EmailVar(s, target), Emails(s) and EmailsVar(s, target) helpers, used as:
+//
+//	from := Email(app.Flag("from", "Sender."))
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"flag"
+	"go/format"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
+
+	"gopkg.in/yaml.v3"
 )
 
 const (
-	tmpl = `package kingpin
-
+	tmpl = `package {{.Package}}
+{{if or (index .Imports 0) (index .Imports 1)}}
+import (
+{{- range index .Imports 0}}
+	{{printf "%q" .}}
+{{- end}}
+{{if and (index .Imports 0) (index .Imports 1)}}
+{{end}}
+{{- range index .Imports 1}}
+	{{printf "%q" .}}
+{{- end}}
+)
+{{end}}
 // This file is autogenerated by "go generate .". Do not modify.
 
-{{range .}}
+{{range .Values}}
 {{if not .NoValueParser}}
 // -- {{.Type}} Value
 type {{.|ValueName}} struct { v *{{.Type}} }
@@ -33,12 +71,15 @@ func (f *{{.|ValueName}}) Set(s string) error {
 func (f *{{.|ValueName}}) Get() interface{} { return ({{.Type}})(*f.v) }
 
 func (f *{{.|ValueName}}) String() string { return {{.|Format}} }
-
+{{if .PlaceHolder}}
+func (f *{{.|ValueName}}) PlaceHolder() string { return {{printf "%q" .PlaceHolder}} }
+{{end}}
 {{if .Help}}
 // {{.Help}}
 {{else -}}
 // {{.|Name}} parses the next command-line value as {{.Type}}.
 {{end -}}
+{{if $.Internal -}}
 func (p *parserMixin) {{.|Name}}() (target *{{.Type}}) {
 	target = new({{.Type}})
 	p.{{.|Name}}Var(target)
@@ -48,33 +89,112 @@ func (p *parserMixin) {{.|Name}}() (target *{{.Type}}) {
 func (p *parserMixin) {{.|Name}}Var(target *{{.Type}}) {
 	p.SetValue(new{{.|Name}}Value(target))
 }
+{{- else -}}
+func {{.|Name}}(s kingpin.Settings) (target *{{.Type}}) {
+	target = new({{.Type}})
+	{{.|Name}}Var(s, target)
+	return
+}
+
+func {{.|Name}}Var(s kingpin.Settings, target *{{.Type}}) {
+	s.SetValue(new{{.|Name}}Value(target))
+}
+{{- end}}
 
 {{end}}
 // {{.|Plural}} accumulates {{.Type}} values into a slice.
-func (p *parserMixin) {{.|Plural}}() (target *[]{{.Type}}) {
+{{if $.Internal -}}
+func (p *parserMixin) {{.|Plural}}({{.Args}}) (target *[]{{.Type}}) {
 	target = new([]{{.Type}})
-	p.{{.|Plural}}Var(target)
+	p.{{.|Plural}}Var(target{{.|ArgNames}})
 	return
 }
 
-func (p *parserMixin) {{.|Plural}}Var(target *[]{{.Type}}) {
+func (p *parserMixin) {{.|Plural}}Var(target *[]{{.Type}}{{if .Args}}, {{.Args}}{{end}}) {
 	p.SetValue(newAccumulator(target, func(v interface{}) Value {
-		return new{{.|Name}}Value(v.(*{{.Type}}))
+		return new{{.|Name}}Value(v.(*{{.Type}}){{.|ArgNames}})
 	}))
 }
+{{- else -}}
+func {{.|Plural}}(s kingpin.Settings{{if .Args}}, {{.Args}}{{end}}) (target *[]{{.Type}}) {
+	target = new([]{{.Type}})
+	{{.|Plural}}Var(s, target{{.|ArgNames}})
+	return
+}
+
+func {{.|Plural}}Var(s kingpin.Settings, target *[]{{.Type}}{{if .Args}}, {{.Args}}{{end}}) {
+	s.SetValue(&{{.|SliceValueName}}{v: target, value: func(v *{{.Type}}) kingpin.Value {
+		return new{{.|Name}}Value(v{{.|ArgNames}})
+	}})
+}
+
+// -- []{{.Type}} Value
+type {{.|SliceValueName}} struct {
+	v     *[]{{.Type}}
+	value func(*{{.Type}}) kingpin.Value
+}
+
+func (f *{{.|SliceValueName}}) Set(s string) error {
+	var v {{.Type}}
+	if err := f.value(&v).Set(s); err != nil {
+		return err
+	}
+	*f.v = append(*f.v, v)
+	return nil
+}
+
+func (f *{{.|SliceValueName}}) Get() interface{} { return *f.v }
+
+func (f *{{.|SliceValueName}}) String() string {
+	out := make([]string, 0, len(*f.v))
+	for i := range *f.v {
+		out = append(out, f.value(&(*f.v)[i]).String())
+	}
+	return strings.Join(out, ",")
+}
+
+func (f *{{.|SliceValueName}}) IsCumulative() bool { return true }
+{{- end}}
 
 {{end}}
 `
 )
 
+// Spec is the list of values to generate, and the imports they need.
+type Spec struct {
+	Imports []string `json:"imports" yaml:"imports"`
+	Values  []Value  `json:"values" yaml:"values"`
+}
+
+type specObject Spec
+
+// UnmarshalJSON accepts either a list of values or an object.
+func (s *Spec) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, &s.Values)
+	}
+	return json.Unmarshal(data, (*specObject)(s))
+}
+
+// UnmarshalYAML accepts either a list of values or an object.
+func (s *Spec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		return node.Decode(&s.Values)
+	}
+	return node.Decode((*specObject)(s))
+}
+
 type Value struct {
-	Name          string `json:"name"`
-	NoValueParser bool   `json:"no_value_parser"`
-	Type          string `json:"type"`
-	Parser        string `json:"parser"`
-	Format        string `json:"format"`
-	Plural        string `json:"plural"`
-	Help          string `json:"help"`
+	Name          string `json:"name" yaml:"name"`
+	NoValueParser bool   `json:"no_value_parser" yaml:"no_value_parser"`
+	Type          string `json:"type" yaml:"type"`
+	Parser        string `json:"parser" yaml:"parser"`
+	Format        string `json:"format" yaml:"format"`
+	Plural        string `json:"plural" yaml:"plural"`
+	Help          string `json:"help" yaml:"help"`
+	PlaceHolder   string `json:"placeholder" yaml:"placeholder"`
+	// Extra parameters of new<Name>Value, eg. "layouts ...string".
+	Args string `json:"args" yaml:"args"`
 }
 
 func fatalIfError(err error) {
@@ -83,14 +203,74 @@ func fatalIfError(err error) {
 	}
 }
 
+// Remove adjacent duplicates from sorted.
+func compact(sorted []string) []string {
+	out := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func readSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, spec)
+	default:
+		err = json.Unmarshal(data, spec)
+	}
+	return spec, err
+}
+
 func main() {
-	r, err := os.Open("values.json")
-	fatalIfError(err)
-	defer r.Close()
+	specPath := flag.String("spec", "values.json", "JSON or YAML spec of the values to generate.")
+	pkg := flag.String("package", "kingpin", "Package of the generated file.")
+	output := flag.String("o", "values_generated.go", "Path of the generated file.")
+	flag.Parse()
 
-	v := []Value{}
-	err = json.NewDecoder(r).Decode(&v)
+	spec, err := readSpec(*specPath)
+	fatalIfError(err)
+	source, err := generate(spec, *pkg)
 	fatalIfError(err)
+	err = os.WriteFile(*output, source, 0o666)
+	fatalIfError(err)
+
+	// Tidy up imports if goimports is available.
+	if goimports, err := exec.LookPath("goimports"); err == nil {
+		err = exec.Command(goimports, "-w", *output).Run()
+		fatalIfError(err)
+	}
+}
+
+// Generate the formatted source of values for spec in package pkg.
+func generate(spec *Spec, pkg string) ([]byte, error) {
+	internal := pkg == "kingpin"
+	imports := append([]string{}, spec.Imports...)
+	if !internal {
+		imports = append(imports, "strings", "github.com/alecthomas/kingpin/v2")
+		for _, v := range spec.Values {
+			if !v.NoValueParser && v.Format == "" {
+				imports = append(imports, "fmt")
+			}
+		}
+	}
+	sort.Strings(imports)
+	// Group standard library imports before the rest.
+	var groups [2][]string
+	for _, path := range compact(imports) {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			groups[1] = append(groups[1], path)
+		} else {
+			groups[0] = append(groups[0], path)
+		}
+	}
 
 	valueName := func(v *Value) string {
 		if v.Name != "" {
@@ -98,6 +278,9 @@ func main() {
 		}
 		return strings.Title(v.Type)
 	}
+	lowerFirst := func(s string) string {
+		return strings.ToLower(s[0:1]) + s[1:]
+	}
 
 	t, err := template.New("genvalues").Funcs(template.FuncMap{
 		"Lower": strings.ToLower,
@@ -108,10 +291,26 @@ func main() {
 			return "fmt.Sprintf(\"%v\", *f.v)"
 		},
 		"ValueName": func(v *Value) string {
-			name := valueName(v)
-			return strings.ToLower(name[0:1]) + name[1:] + "Value"
+			return lowerFirst(valueName(v)) + "Value"
+		},
+		"SliceValueName": func(v *Value) string {
+			return lowerFirst(valueName(v)) + "SliceValue"
 		},
 		"Name": valueName,
+		"ArgNames": func(v *Value) string {
+			out := ""
+			for _, arg := range strings.Split(v.Args, ",") {
+				parts := strings.Fields(arg)
+				if len(parts) == 0 {
+					continue
+				}
+				out += ", " + parts[0]
+				if strings.HasPrefix(parts[len(parts)-1], "...") {
+					out += "..."
+				}
+			}
+			return out
+		},
 		"Plural": func(v *Value) string {
 			if v.Plural != "" {
 				return v.Plural
@@ -119,15 +318,19 @@ func main() {
 			return valueName(v) + "List"
 		},
 	}).Parse(tmpl)
-	fatalIfError(err)
-
-	w, err := os.Create("values_generated.go")
-	fatalIfError(err)
-	defer w.Close()
-
-	err = t.Execute(w, v)
-	fatalIfError(err)
+	if err != nil {
+		return nil, err
+	}
 
-	err = exec.Command("goimports", "-w", "values_generated.go").Run()
-	fatalIfError(err)
+	w := &bytes.Buffer{}
+	err = t.Execute(w, map[string]interface{}{
+		"Package":  pkg,
+		"Internal": internal,
+		"Imports":  groups,
+		"Values":   spec.Values,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(w.Bytes())
 }
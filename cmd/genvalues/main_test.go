@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "Update golden files.")
+
+func TestGenerateExternal(t *testing.T) {
+	spec, err := readSpec("testdata/values.yaml")
+	assert.NoError(t, err)
+	source, err := generate(spec, "main")
+	assert.NoError(t, err)
+
+	golden := filepath.Join("testdata", "values.go.golden")
+	if *update {
+		assert.NoError(t, os.WriteFile(golden, source, 0o666))
+	}
+	expected, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(source))
+}
+
+// Build a program using the generated values, in a directory under testdata so
+// it is part of this module but not matched by ./...
+func TestGenerateExternalCompiles(t *testing.T) {
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found in $PATH")
+	}
+	spec, err := readSpec("testdata/values.yaml")
+	assert.NoError(t, err)
+	source, err := generate(spec, "main")
+	assert.NoError(t, err)
+
+	dir, err := os.MkdirTemp("testdata", "build")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "values_generated.go"), source, 0o666))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package main
+
+import (
+	"net/mail"
+
+	"github.com/alecthomas/kingpin/v2"
+)
+
+func main() {
+	app := kingpin.New("test", "")
+	from := Email(app.Flag("from", ""))
+	var to []*mail.Address
+	EmailsVar(app.Flag("to", ""), &to)
+	levels := LevelList(app.Flag("level", ""))
+	kingpin.MustParse(app.Parse([]string{"--from=a@example.com", "--to=b@example.com", "--level=1"}))
+	_, _ = from, levels
+}
+`), 0o666))
+	output, err := exec.Command(gobin, "vet", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	assert.NoError(t, err, "%s", output)
+}
@@ -6,6 +6,8 @@ type HintAction func() []string
 type completionsMixin struct {
 	hintActions        []HintAction
 	builtinHintActions []HintAction
+	// Describes the builtin completions, eg. the options of an enum.
+	describeBuiltin func(option string) string
 }
 
 func (a *completionsMixin) addHintAction(action HintAction) {
@@ -31,3 +33,18 @@ func (a *completionsMixin) resolveCompletions() []string {
 	}
 	return hints
 }
+
+// Append the description of each option, if any, separated by a tab.
+func (a *completionsMixin) describeCompletions(options []string) []string {
+	if a.describeBuiltin == nil || len(a.hintActions) > 0 {
+		return options
+	}
+	out := make([]string, len(options))
+	for i, option := range options {
+		out[i] = option
+		if description := a.describeBuiltin(option); description != "" {
+			out[i] += "\t" + description
+		}
+	}
+	return out
+}
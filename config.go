@@ -0,0 +1,377 @@
+package kingpin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigLoader decodes a configuration file into a tree of nested maps,
+// slices and scalar values.
+//
+// Top-level keys map to application flags and arguments by name. Keys nested
+// under a command name map to the flags and arguments of that command, eg.
+//
+//	{"debug": true, "db": {"migrate": {"dry-run": true}}}
+type ConfigLoader func(r io.Reader) (map[string]interface{}, error)
+
+var (
+	defaultConfigLoaders = map[string]ConfigLoader{
+		".json": JSONConfigLoader,
+		".yaml": YAMLConfigLoader,
+		".yml":  YAMLConfigLoader,
+		".toml": INIConfigLoader,
+		".ini":  INIConfigLoader,
+	}
+)
+
+// ConfigFile sets the paths of configuration files to load default values
+// from. The first path that exists is used.
+//
+// Values are applied with the following precedence: command line, envar,
+// configuration file, then Default().
+func (a *Application) ConfigFile(paths ...string) *Application {
+	a.configFiles = paths
+	return a
+}
+
+// ConfigFlag adds a flag whose value, if provided on the command line or via
+// its envar, is the path of the configuration file to load. It overrides any
+// paths passed to ConfigFile().
+func (a *Application) ConfigFlag(name, help string) *FlagClause {
+	a.configFlag = a.Flag(name, help)
+	a.configFlag.String()
+	return a.configFlag
+}
+
+// ConfigLoader registers a loader for configuration files with the given
+// extension (eg. ".hcl"), replacing any existing loader.
+func (a *Application) ConfigLoader(ext string, loader ConfigLoader) *Application {
+	if a.configLoaders == nil {
+		a.configLoaders = map[string]ConfigLoader{}
+	}
+	a.configLoaders[ext] = loader
+	return a
+}
+
+func (a *Application) configFilePath(context *ParseContext) (path string, explicit bool, err error) {
+	if a.configFlag != nil {
+		for _, element := range context.Elements {
+			if flag, ok := element.Clause.(*FlagClause); ok && flag == a.configFlag {
+				path = *element.Value
+			}
+		}
+		if path != "" {
+			return path, true, nil
+		}
+		if path, err = a.configFlag.readEnvarValue(); path != "" || err != nil {
+			return path, true, err
+		}
+	}
+	for _, path = range a.configFiles {
+		if _, err := os.Stat(path); err == nil {
+			return path, false, nil
+		}
+	}
+	return "", false, nil
+}
+
+func (a *Application) loadConfig(context *ParseContext) error {
+	path, explicit, err := a.configFilePath(context)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return nil
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	loader, ok := a.configLoaders[ext]
+	if !ok {
+		loader, ok = defaultConfigLoaders[ext]
+	}
+	if !ok {
+		return fmt.Errorf("unsupported configuration file format %q", path)
+	}
+	r, err := os.Open(path)
+	if os.IsNotExist(err) && !explicit {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to open configuration file: %s", err)
+	}
+	defer r.Close()
+	tree, err := loader(r)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration file %q: %s", path, err)
+	}
+
+	context.configFile = path
+	context.config = map[interface{}]*configValue{}
+	scopes := []*cmdMixin{&a.cmdMixin}
+	prefixes := [][]string{nil}
+	for _, element := range context.Elements {
+		if cmd, ok := element.Clause.(*CmdClause); ok {
+			scopes = append(scopes, &cmd.cmdMixin)
+			prefixes = append(prefixes, strings.Split(cmd.FullCommand(), " "))
+		}
+	}
+	for i, scope := range scopes {
+		prefix := prefixes[i]
+		for _, flag := range scope.flagOrder {
+			if err := context.resolveConfig(tree, flag, append(prefix[:len(prefix):len(prefix)], flag.name)); err != nil {
+				return err
+			}
+		}
+		for _, arg := range scope.args {
+			if err := context.resolveConfig(tree, arg, append(prefix[:len(prefix):len(prefix)], arg.name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// A configValue is the value(s) for a single flag or argument found in a
+// configuration file.
+type configValue struct {
+	key    string
+	values []string
+}
+
+func (p *ParseContext) resolveConfig(tree map[string]interface{}, clause interface{}, path []string) error {
+	var node interface{} = tree
+	for _, key := range path {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if node, ok = m[key]; !ok || node == nil {
+			return nil
+		}
+	}
+	key := strings.Join(path, ".")
+	values, err := configStrings(node)
+	if err != nil {
+		return fmt.Errorf("%s: invalid value for %q: %s", p.configFile, key, err)
+	}
+	p.config[clause] = &configValue{key: key, values: values}
+	return nil
+}
+
+// Convert a leaf of a configuration tree into the string value(s) passed to
+// Value.Set().
+func configStrings(node interface{}) ([]string, error) {
+	switch node := node.(type) {
+	case string:
+		return []string{node}, nil
+	case float64:
+		return []string{strconv.FormatFloat(node, 'f', -1, 64)}, nil
+	case []interface{}:
+		out := []string{}
+		for _, element := range node {
+			switch element.(type) {
+			case []interface{}, map[string]interface{}:
+				return nil, fmt.Errorf("nested lists are not supported")
+			}
+			values, err := configStrings(element)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, values...)
+		}
+		return out, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(node))
+		for key := range node {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		out := []string{}
+		for _, key := range keys {
+			values, err := configStrings(node[key])
+			if err != nil {
+				return nil, err
+			}
+			if len(values) != 1 {
+				return nil, fmt.Errorf("expected a single value for key %q", key)
+			}
+			out = append(out, key+"="+values[0])
+		}
+		return out, nil
+	default:
+		return []string{fmt.Sprintf("%v", node)}, nil
+	}
+}
+
+// JSONConfigLoader loads JSON configuration files.
+func JSONConfigLoader(r io.Reader) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	if err := json.NewDecoder(r).Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// YAMLConfigLoader loads YAML configuration files.
+func YAMLConfigLoader(r io.Reader) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	if err := yaml.NewDecoder(r).Decode(&out); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return out, nil
+}
+
+// INIConfigLoader loads INI files and the commonly used subset of TOML:
+// [section.subsection] headers, key = value pairs, quoted strings and single
+// line arrays. Lines starting with # or ; are comments, as is the rest of a
+// line after an unquoted # or ; that follows whitespace, eg. "port = 80 # http".
+func INIConfigLoader(r io.Reader) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	section := out
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(stripINIComment(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if strings.HasPrefix(line, "[[") || !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: invalid section %q", n, line)
+			}
+			var err error
+			section, err = iniSection(out, strings.Split(strings.TrimSpace(line[1:len(line)-1]), "."))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", n, err)
+			}
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", n, line)
+		}
+		path := strings.Split(strings.TrimSpace(parts[0]), ".")
+		parent, err := iniSection(section, path[:len(path)-1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		value, err := iniValue(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		parent[strings.TrimSpace(path[len(path)-1])] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func iniSection(root map[string]interface{}, path []string) (map[string]interface{}, error) {
+	section := root
+	for _, key := range path {
+		key = strings.TrimSpace(key)
+		child, ok := section[key]
+		if !ok {
+			child = map[string]interface{}{}
+			section[key] = child
+		}
+		if section, ok = child.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("%q is not a section", key)
+		}
+	}
+	return section, nil
+}
+
+func iniValue(value string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(value, "["):
+		if !strings.HasSuffix(value, "]") {
+			return nil, fmt.Errorf("unterminated array %q", value)
+		}
+		out := []interface{}{}
+		for _, element := range splitINIArray(value[1 : len(value)-1]) {
+			v, err := iniValue(element)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case strings.HasPrefix(value, `"`):
+		return strconv.Unquote(value)
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return nil, fmt.Errorf("unterminated string %s", value)
+		}
+		return value[1 : len(value)-1], nil
+	}
+	return value, nil
+}
+
+// Remove a trailing comment from line, ignoring # and ; in quoted strings or
+// not preceded by whitespace, as in "http://host/#anchor". Quotes only start a
+// string at the start of a value, so "it's" is not quoted.
+func stripINIComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || line[i-1] != '\\') {
+				quote = 0
+			}
+		case (r == '"' || r == '\'') && i > 0 && strings.ContainsRune("= \t[,", rune(line[i-1])):
+			quote = r
+		case (r == '#' || r == ';') && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// Split the elements of an array, respecting quoted strings.
+func splitINIArray(s string) []string {
+	out := []string{}
+	start := 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || i == 0 || s[i-1] != '\\') {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	out = append(out, s[start:])
+	elements := out[:0]
+	for _, element := range out {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// Apply a value loaded from the configuration file.
+func (p *ParseContext) applyConfig(config *configValue, value Value) error {
+	if r, ok := value.(repeatableFlag); (!ok || !r.IsCumulative()) && len(config.values) > 1 {
+		return fmt.Errorf("%s: invalid value for %q, expecting single value", p.configFile, config.key)
+	}
+	for _, v := range config.values {
+		if err := value.Set(v); err != nil {
+			return fmt.Errorf("%s: invalid value for %q: %s", p.configFile, config.key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package kingpin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "kingpin")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestConfigFilePrecedence(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"a": "config", "b": "config", "c": "config"}`)
+	os.Setenv("TEST_CONFIG_B", "envar")
+	defer os.Unsetenv("TEST_CONFIG_B")
+	app := newTestApp().ConfigFile(path)
+	a := app.Flag("a", "").Default("default").String()
+	b := app.Flag("b", "").Envar("TEST_CONFIG_B").Default("default").String()
+	c := app.Flag("c", "").Default("default").String()
+	d := app.Flag("d", "").Default("default").String()
+	_, err := app.Parse([]string{"--a=flag"})
+	assert.NoError(t, err)
+	assert.Equal(t, "flag", *a)
+	assert.Equal(t, "envar", *b)
+	assert.Equal(t, "config", *c)
+	assert.Equal(t, "default", *d)
+}
+
+func TestConfigFileSubcommands(t *testing.T) {
+	path := writeTempFile(t, "config.yaml", strings.Join([]string{
+		"debug: true",
+		"db:",
+		"  migrate:",
+		"    dry-run: true",
+		"    steps: 3",
+		"    target: head",
+		"    tags: [a, b]",
+	}, "\n"))
+	app := newTestApp().ConfigFile(path)
+	debug := app.Flag("debug", "").Bool()
+	migrate := app.Command("db", "").Command("migrate", "")
+	dryRun := migrate.Flag("dry-run", "").Bool()
+	steps := migrate.Flag("steps", "").Int()
+	tags := migrate.Flag("tags", "").Strings()
+	target := migrate.Arg("target", "").Required().String()
+	selected, err := app.Parse([]string{"db", "migrate"})
+	assert.NoError(t, err)
+	assert.Equal(t, "db migrate", selected)
+	assert.True(t, *debug)
+	assert.True(t, *dryRun)
+	assert.Equal(t, 3, *steps)
+	assert.Equal(t, []string{"a", "b"}, *tags)
+	assert.Equal(t, "head", *target)
+}
+
+func TestConfigFileINI(t *testing.T) {
+	path := writeTempFile(t, "config.toml", strings.Join([]string{
+		"# Comment",
+		`name = "Harry"`,
+		`hosts = ["a:1", "b:2"]`,
+		"[server]",
+		"port = 8080",
+		"labels.env = prod",
+	}, "\n"))
+	app := newTestApp().ConfigFile(path)
+	name := app.Flag("name", "").String()
+	hosts := app.Flag("hosts", "").Strings()
+	server := app.Command("server", "")
+	port := server.Flag("port", "").Int()
+	labels := server.Flag("labels", "").StringMap()
+	_, err := app.Parse([]string{"server"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Harry", *name)
+	assert.Equal(t, []string{"a:1", "b:2"}, *hosts)
+	assert.Equal(t, 8080, *port)
+	assert.Equal(t, map[string]string{"env": "prod"}, *labels)
+}
+
+func TestConfigFileINIComments(t *testing.T) {
+	path := writeTempFile(t, "config.ini", strings.Join([]string{
+		"[server] ; Server options",
+		"port = 8080 # http",
+		`name = "a # b" ; quoted`,
+		"motd = 'x;y' # single quoted",
+		`url = http://example.com/#top`,
+		"tags = [a, b] # two",
+		"path=a;b",
+		"owner = O'Brien # name",
+	}, "\n"))
+	app := newTestApp().ConfigFile(path)
+	server := app.Command("server", "")
+	port := server.Flag("port", "").Int()
+	name := server.Flag("name", "").String()
+	motd := server.Flag("motd", "").String()
+	url := server.Flag("url", "").String()
+	tags := server.Flag("tags", "").Strings()
+	p := server.Flag("path", "").String()
+	owner := server.Flag("owner", "").String()
+	_, err := app.Parse([]string{"server"})
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, *port)
+	assert.Equal(t, "a # b", *name)
+	assert.Equal(t, "x;y", *motd)
+	assert.Equal(t, "http://example.com/#top", *url)
+	assert.Equal(t, []string{"a", "b"}, *tags)
+	assert.Equal(t, "a;b", *p)
+	assert.Equal(t, "O'Brien", *owner)
+}
+
+func TestConfigFlag(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"a": "config"}`)
+	app := newTestApp().ConfigFile("/DEFINITELYMISSING.json")
+	app.ConfigFlag("config", "")
+	a := app.Flag("a", "").String()
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", *a)
+	_, err = app.Parse([]string{"--config", path})
+	assert.NoError(t, err)
+	assert.Equal(t, "config", *a)
+	_, err = app.Parse([]string{"--config", path + ".missing.json"})
+	assert.Error(t, err)
+}
+
+func TestConfigFlagEnvarFileError(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"a": "config"}`)
+	t.Setenv("TEST_CONFIG_FILE", path+".missing")
+	app := newTestApp().ConfigFile(path)
+	app.ConfigFlag("config", "").Envar("TEST_CONFIG").EnvarFile()
+	assert.NoError(t, app.init())
+	_, _, err := app.configFilePath(&ParseContext{})
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "failed to read $TEST_CONFIG_FILE: "), err.Error())
+}
+
+func TestConfigFileSatisfiesRequired(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"a": "config"}`)
+	app := newTestApp().ConfigFile(path)
+	a := app.Flag("a", "").Required().String()
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "config", *a)
+}
+
+func TestConfigFileInvalidValue(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"a": "one", "b": ["1", "2"]}`)
+	app := newTestApp().ConfigFile(path)
+	app.Flag("a", "").Int()
+	_, err := app.Parse([]string{})
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), path+`: invalid value for "a": `), err.Error())
+
+	app = newTestApp().ConfigFile(path)
+	app.Flag("b", "").Int()
+	_, err = app.Parse([]string{})
+	assert.EqualError(t, err, path+`: invalid value for "b", expecting single value`)
+}
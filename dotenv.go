@@ -0,0 +1,143 @@
+package kingpin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	dotEnvLineRegexp  = regexp.MustCompile(`^(?:export\s+)?([A-Za-z_][A-Za-z0-9_.]*)\s*=\s*(.*)$`)
+	dotEnvExpandRegex = regexp.MustCompile(`\\?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// DotEnv loads environment variables for flag and argument envars from the
+// given "dotenv" files, if they exist. The process environment is not
+// modified, and variables set in it take precedence over those in the files.
+// Variables in earlier files take precedence over those in later files.
+//
+// Each line of a file is of the form KEY=VALUE, optionally prefixed with
+// "export". Lines starting with # are comments. Values may be single quoted,
+// or double quoted in which case escape sequences and ${VAR} references are
+// expanded. ${VAR} references are also expanded in unquoted values.
+func (a *Application) DotEnv(paths ...string) *Application {
+	a.dotEnvFiles = paths
+	return a
+}
+
+func (a *Application) loadDotEnv() error {
+	if len(a.dotEnvFiles) == 0 {
+		return nil
+	}
+	a.dotEnv = map[string]string{}
+	for _, path := range a.dotEnvFiles {
+		r, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		vars, err := parseDotEnv(r, a.lookupEnvar)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err)
+		}
+		for key, value := range vars {
+			if _, ok := a.dotEnv[key]; !ok {
+				a.dotEnv[key] = value
+			}
+		}
+	}
+	a.eachEnvar(func(e *envarMixin) { e.lookupEnvar = a.lookupEnvar })
+	return nil
+}
+
+// Look up an environment variable from the process environment, then any
+// dotenv files.
+func (a *Application) lookupEnvar(name string) (string, bool) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, true
+	}
+	value, ok := a.dotEnv[name]
+	return value, ok
+}
+
+func parseDotEnv(r io.Reader, lookup func(string) (string, bool)) (map[string]string, error) {
+	out := map[string]string{}
+	expand := func(s string) string {
+		return dotEnvExpandRegex.ReplaceAllStringFunc(s, func(ref string) string {
+			if strings.HasPrefix(ref, `\`) {
+				return ref[1:]
+			}
+			name := ref[2 : len(ref)-1]
+			if value, ok := lookup(name); ok {
+				return value
+			}
+			return out[name]
+		})
+	}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		groups := dotEnvLineRegexp.FindStringSubmatch(line)
+		if groups == nil {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE, got %q", n, line)
+		}
+		key, value := groups[1], groups[2]
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string", n)
+			}
+			value = value[1 : end+1]
+
+		case strings.HasPrefix(value, `"`):
+			// Double quoted values may span multiple lines.
+			for !dotEnvTerminated(value) && scanner.Scan() {
+				value += "\n" + scanner.Text()
+				n++
+			}
+			end := dotEnvTerminator(value)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string", n)
+			}
+			value = expand(dotEnvUnescape(value[1:end]))
+
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = value[:i]
+			}
+			value = expand(strings.TrimSpace(value))
+		}
+		out[key] = value
+	}
+	return out, scanner.Err()
+}
+
+// Index of the closing quote of a double quoted value, or -1.
+func dotEnvTerminator(value string) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func dotEnvTerminated(value string) bool {
+	return dotEnvTerminator(value) >= 0
+}
+
+func dotEnvUnescape(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(value)
+}
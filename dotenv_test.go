@@ -0,0 +1,61 @@
+package kingpin
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	env := map[string]string{"TEST_DOTENV_HOME": "/home/harry"}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	vars, err := parseDotEnv(strings.NewReader(strings.Join([]string{
+		"# Comment",
+		"",
+		"A=plain value # trailing comment",
+		"export B = 'single ${A} #'",
+		`C="double\t${A}\n\"quoted\""`,
+		"D=${TEST_DOTENV_HOME}/bin:${MISSING}",
+		`E="multi`,
+		`line"`,
+		`F=\${A}`,
+	}, "\n")), lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"A": "plain value",
+		"B": "single ${A} #",
+		"C": "double\tplain value\n\"quoted\"",
+		"D": "/home/harry/bin:",
+		"E": "multi\nline",
+		"F": "${A}",
+	}, vars)
+
+	_, err = parseDotEnv(strings.NewReader("A=1\nnot a variable"), lookup)
+	assert.EqualError(t, err, `line 2: expected KEY=VALUE, got "not a variable"`)
+	_, err = parseDotEnv(strings.NewReader(`A="unterminated`), lookup)
+	assert.EqualError(t, err, `line 1: unterminated string`)
+}
+
+func TestDotEnv(t *testing.T) {
+	local := writeTempFile(t, ".env.local", "TEST_DOTENV_A=local\n")
+	path := writeTempFile(t, ".env", "TEST_DOTENV_A=file\nTEST_DOTENV_B=file\nTEST_DOTENV_C=file\n")
+	os.Setenv("TEST_DOTENV_B", "envar")
+	defer os.Unsetenv("TEST_DOTENV_B")
+	app := newTestApp().DotEnv(local, path, "/DEFINITELYMISSING/.env")
+	a := app.Flag("a", "").Envar("TEST_DOTENV_A").String()
+	b := app.Flag("b", "").Envar("TEST_DOTENV_B").String()
+	c := app.Arg("c", "").Envar("TEST_DOTENV_C").String()
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "local", *a)
+	assert.Equal(t, "envar", *b)
+	assert.Equal(t, "file", *c)
+	assert.Equal(t, SourceEnvar, app.GetFlag("a").Source().Kind)
+	_, ok := os.LookupEnv("TEST_DOTENV_A")
+	assert.False(t, ok)
+}
@@ -1,8 +1,12 @@
 package kingpin
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
 	"regexp"
+	"strings"
+	"unicode"
 )
 
 var (
@@ -12,29 +16,214 @@ var (
 )
 
 type envarMixin struct {
-	envar   string
-	noEnvar bool
+	envar          string
+	noEnvar        bool
+	envarFile      bool   // Read the value from the file named by <envar>_FILE.
+	envarSeparator string // Separator between multiple values, defaults to newlines.
+	// Looks up environment variables, defaults to os.LookupEnv.
+	lookupEnvar func(string) (string, bool)
 }
 
-func (e *envarMixin) HasEnvarValue() bool {
-	return e.GetEnvarValue() != ""
+func (e *envarMixin) getenv(name string) string {
+	if e.lookupEnvar == nil {
+		return os.Getenv(name)
+	}
+	value, _ := e.lookupEnvar(name)
+	return value
 }
 
-func (e *envarMixin) GetEnvarValue() string {
+// Name of the environment variable providing the value, if any.
+func (e *envarMixin) envarName() string {
 	if e.noEnvar || e.envar == "" {
 		return ""
 	}
-	return os.Getenv(e.envar)
+	if e.getenv(e.envar) != "" {
+		return e.envar
+	}
+	if e.envarFile && e.getenv(e.envar+"_FILE") != "" {
+		return e.envar + "_FILE"
+	}
+	return ""
+}
+
+func (e *envarMixin) HasEnvarValue() bool {
+	return e.envarName() != ""
+}
+
+func (e *envarMixin) GetEnvarValue() string {
+	value, _ := e.readEnvarValue()
+	return value
+}
+
+func (e *envarMixin) readEnvarValue() (string, error) {
+	name := e.envarName()
+	switch name {
+	case "":
+		return "", nil
+	case e.envar:
+		return e.getenv(name), nil
+	}
+	data, err := ioutil.ReadFile(e.getenv(name))
+	if err != nil {
+		return "", fmt.Errorf("failed to read $%s: %s", name, err)
+	}
+	return envVarValuesTrimmer.ReplaceAllString(string(data), ""), nil
 }
 
 func (e *envarMixin) GetSplitEnvarValue() []string {
-	envarValue := e.GetEnvarValue()
+	return e.splitEnvarValue(e.GetEnvarValue())
+}
+
+func (e *envarMixin) splitEnvarValue(envarValue string) []string {
 	if envarValue == "" {
 		return []string{}
 	}
 
+	if e.envarSeparator != "" {
+		return splitList(envarValue, e.envarSeparator)
+	}
+
 	// Split by new line to extract multiple values, if any.
 	trimmed := envVarValuesTrimmer.ReplaceAllString(envarValue, "")
 
 	return envVarValuesSplitter.Split(trimmed, -1)
 }
+
+// Set value from the environment, splitting multiple values if the value is
+// cumulative.
+func (e *envarMixin) setEnvarValue(value Value) error {
+	envarValue, err := e.readEnvarValue()
+	if err != nil {
+		return err
+	}
+	if r, ok := value.(repeatableFlag); !ok || !r.IsCumulative() {
+		// Use the value as-is
+		return value.Set(envarValue)
+	}
+	for _, v := range e.splitEnvarValue(envarValue) {
+		if err := value.Set(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Split s on sep, trimming whitespace from each element and discarding empty
+// elements. Elements may be single or double quoted to include the separator
+// or surrounding whitespace, and a separator or quote may be escaped with a
+// backslash. Quotes are only special at the start of an element and when they
+// are terminated, so "it's" is kept as is.
+func splitList(s, sep string) []string {
+	out := []string{}
+	element := strings.Builder{}
+	quoted := false // Element contains quotes.
+	keep := 0       // Length of the element that must not be trimmed.
+	var quote byte  // Current quote character.
+	flush := func() {
+		v := element.String()
+		v = v[:keep] + strings.TrimRightFunc(v[keep:], unicode.IsSpace)
+		if v != "" || quoted {
+			out = append(out, v)
+		}
+		element.Reset()
+		quoted, keep = false, 0
+	}
+	for i := 0; i < len(s); {
+		switch {
+		case quote != 0 && s[i] == quote:
+			quote = 0
+			keep = element.Len()
+			i++
+		case quote == 0 && element.Len() == 0 && !quoted && (s[i] == '"' || s[i] == '\'') && closingQuote(s[i+1:], s[i]):
+			quote, quoted = s[i], true
+			i++
+		case s[i] == '\\' && quote == 0 && strings.HasPrefix(s[i+1:], sep):
+			element.WriteString(sep)
+			keep = element.Len()
+			i += 1 + len(sep)
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == '\\' || s[i+1] == '"' || s[i+1] == '\''):
+			element.WriteByte(s[i+1])
+			keep = element.Len()
+			i += 2
+		case quote == 0 && strings.HasPrefix(s[i:], sep):
+			flush()
+			i += len(sep)
+		case quote == 0 && element.Len() == 0 && unicode.IsSpace(rune(s[i])):
+			i++
+		default:
+			element.WriteByte(s[i])
+			if quote != 0 {
+				keep = element.Len()
+			}
+			i++
+		}
+	}
+	flush()
+	return out
+}
+
+// Whether s contains an unescaped quote.
+func closingQuote(s string, quote byte) bool {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return true
+		}
+	}
+	return false
+}
+
+// Describe a list separator for help, eg. "comma-separated".
+func formatSeparator(sep string) string {
+	names := map[string]string{
+		",":  "comma",
+		";":  "semicolon",
+		":":  "colon",
+		" ":  "space",
+		"|":  "pipe",
+		"\t": "tab",
+	}
+	if name, ok := names[sep]; ok {
+		return name + "-separated"
+	}
+	return fmt.Sprintf("%q-separated", sep)
+}
+
+// Expand ${VAR} references, with getenv, and a leading ~ in a default value.
+// "\${" is not expanded.
+func expandDefault(value string, getenv func(string) string) (string, error) {
+	value = dotEnvExpandRegex.ReplaceAllStringFunc(value, func(ref string) string {
+		if strings.HasPrefix(ref, `\`) {
+			return ref[1:]
+		}
+		return getenv(ref[2 : len(ref)-1])
+	})
+	return expandHome(value)
+}
+
+// Set value to each of the defaults, expanded if getenv is not nil, or to the
+// result of fn if there are none.
+func setDefaultValues(value Value, defaults []string, fn func() (string, error), getenv func(string) string) error {
+	if len(defaults) == 0 && fn != nil {
+		v, err := fn()
+		if err != nil {
+			return err
+		}
+		return value.Set(v)
+	}
+	for _, defaultValue := range defaults {
+		v := defaultValue
+		if getenv != nil {
+			var err error
+			if v, err = expandDefault(defaultValue, getenv); err != nil {
+				return err
+			}
+		}
+		if err := value.Set(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
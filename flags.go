@@ -2,6 +2,7 @@ package kingpin
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 )
 
@@ -132,7 +133,7 @@ loop:
 				defaultValue = token.Value
 			}
 
-			context.matchedFlag(flag, defaultValue)
+			context.matchedFlag(flag, flagToken.Index, defaultValue)
 			return flag, nil
 
 		default:
@@ -148,13 +149,22 @@ type FlagClause struct {
 	actionMixin
 	completionsMixin
 	envarMixin
-	name          string
-	shorthand     rune
-	help          string
-	defaultValues []string
-	placeholder   string
-	hidden        bool
-	setByUser     *bool
+	name           string
+	shorthand      rune
+	help           string
+	defaultValues  []string
+	defaultFunc    func() (string, error)
+	defaultHelp    string // Shown in help instead of the default values.
+	expandDefault  bool   // Expand ${VAR} and ~ in default values.
+	placeholder    string
+	hidden         bool
+	secret         bool
+	prompt         string
+	separator      string // Separator between multiple values in a single argument.
+	ignoreCase     bool
+	fromFilePrefix string // Prefix of values to read from files, see AllowFromFile().
+	setByUser      *bool
+	source         ValueSource
 }
 
 func newFlag(name, help string) *FlagClause {
@@ -165,46 +175,60 @@ func newFlag(name, help string) *FlagClause {
 	return f
 }
 
-func (f *FlagClause) setDefault() error {
-	if f.HasEnvarValue() {
-		if v, ok := f.value.(repeatableFlag); !ok || !v.IsCumulative() {
-			// Use the value as-is
-			return f.value.Set(f.GetEnvarValue())
-		} else {
-			for _, value := range f.GetSplitEnvarValue() {
-				if err := f.value.Set(value); err != nil {
-					return err
-				}
-			}
-			return nil
-		}
+func (f *FlagClause) setDefault(context *ParseContext) error {
+	if name := f.envarName(); name != "" {
+		f.source = ValueSource{Kind: SourceEnvar, Envar: name}
+		return f.setEnvarValue(f.setter())
 	}
 
-	if len(f.defaultValues) > 0 {
-		for _, defaultValue := range f.defaultValues {
-			if err := f.value.Set(defaultValue); err != nil {
-				return err
-			}
+	if config, ok := context.config[f]; ok {
+		f.source = ValueSource{Kind: SourceConfig, ConfigFile: context.configFile, ConfigKey: config.key}
+		defer withBaseDir(f.value, filepath.Dir(context.configFile))()
+		return context.applyConfig(config, f.setter())
+	}
+
+	if len(f.defaultValues) > 0 || f.defaultFunc != nil {
+		f.source = ValueSource{Kind: SourceDefault}
+		var getenv func(string) string
+		if f.expandDefault {
+			getenv = f.getenv
+		}
+		if err := setDefaultValues(f.setter(), f.defaultValues, f.defaultFunc, getenv); err != nil {
+			return fmt.Errorf("default for '--%s': %s", f.name, err)
 		}
 		return nil
 	}
 
+	f.source = ValueSource{}
 	return nil
 }
 
+// The Value to set the flag through.
+func (f *FlagClause) setter() Value {
+	value := f.value
+	if f.secret {
+		value = maskedValue{value, f.name}
+	}
+	if f.separator != "" {
+		value = &separatedValue{value, f.separator}
+	}
+	return value
+}
+
 func (f *FlagClause) isSetByUser() {
 	if f.setByUser != nil {
 		*f.setByUser = true
 	}
 }
 
-func (f *FlagClause) needsValue() bool {
-	haveDefault := len(f.defaultValues) > 0
-	return f.required && !(haveDefault || f.HasEnvarValue())
+func (f *FlagClause) needsValue(context *ParseContext) bool {
+	haveDefault := len(f.defaultValues) > 0 || f.defaultFunc != nil
+	_, haveConfig := context.config[f]
+	return f.required && !(haveDefault || haveConfig || f.HasEnvarValue())
 }
 
 func (f *FlagClause) init() error {
-	if f.required && len(f.defaultValues) > 0 {
+	if f.required && (len(f.defaultValues) > 0 || f.defaultFunc != nil) {
 		return fmt.Errorf("required flag '--%s' with default value that will never be used", f.name)
 	}
 	if f.value == nil {
@@ -213,6 +237,23 @@ func (f *FlagClause) init() error {
 	if v, ok := f.value.(repeatableFlag); (!ok || !v.IsCumulative()) && len(f.defaultValues) > 1 {
 		return fmt.Errorf("invalid default for '--%s', expecting single value", f.name)
 	}
+	if v, ok := f.value.(repeatableFlag); (!ok || !v.IsCumulative()) && f.separator != "" {
+		return fmt.Errorf("Separator() set on '--%s', which does not accept multiple values", f.name)
+	}
+	if v, ok := f.value.(enumOptionsValue); ok {
+		if f.ignoreCase {
+			v.setIgnoreCase()
+		}
+		if len(f.builtinHintActions) == 0 {
+			f.addHintActionBuiltin(func() []string { return v.getEnumOptions().options })
+			f.describeBuiltin = v.getEnumOptions().describe
+		}
+	} else if f.ignoreCase {
+		return fmt.Errorf("IgnoreCase() for '--%s', which is not an enum", f.name)
+	}
+	if v, ok := f.value.(fromFileValue); ok && f.fromFilePrefix == "" {
+		f.fromFilePrefix = v.fromFilePrefix()
+	}
 	return nil
 }
 
@@ -269,6 +310,28 @@ func (a *FlagClause) Enums(options ...string) (target *[]string) {
 	return a.parserMixin.Enums(options...)
 }
 
+// AllowFromFile reads the value of the flag from a file when it is given as
+// @PATH on the command line, eg. "--cert=@cert.pem", with one trailing
+// newline removed. "@-" reads stdin, which may only be read once per
+// invocation. Such values are not expanded as argument files (see
+// EnableFileExpansion).
+func (f *FlagClause) AllowFromFile() *FlagClause {
+	return f.FromFilePrefix("@")
+}
+
+// FromFilePrefix is like AllowFromFile but with a prefix other than "@".
+func (f *FlagClause) FromFilePrefix(prefix string) *FlagClause {
+	f.fromFilePrefix = prefix
+	return f
+}
+
+// IgnoreCase matches enum options case-insensitively. The value is set to the
+// option as it was defined, eg. "--format=JSON" sets "json".
+func (f *FlagClause) IgnoreCase() *FlagClause {
+	f.ignoreCase = true
+	return f
+}
+
 // IsSetByUser let to know if the flag was set by the user
 func (f *FlagClause) IsSetByUser(setByUser *bool) *FlagClause {
 	if setByUser != nil {
@@ -278,12 +341,39 @@ func (f *FlagClause) IsSetByUser(setByUser *bool) *FlagClause {
 	return f
 }
 
+// Source returns where the value of the flag came from during the most recent
+// parse.
+func (f *FlagClause) Source() ValueSource {
+	return f.source
+}
+
 // Default values for this flag. They *must* be parseable by the value of the flag.
 func (f *FlagClause) Default(values ...string) *FlagClause {
 	f.defaultValues = values
 	return f
 }
 
+// ExpandDefault expands ${VAR} references and a leading ~ in the default
+// values of the flag when they are used. "\${" is not expanded.
+func (f *FlagClause) ExpandDefault() *FlagClause {
+	f.expandDefault = true
+	return f
+}
+
+// DefaultFunc computes the default value of the flag with fn, which is only
+// called if the flag is not otherwise set.
+func (f *FlagClause) DefaultFunc(fn func() (string, error)) *FlagClause {
+	f.defaultFunc = fn
+	return f
+}
+
+// DefaultHelp describes the default value in help instead of showing it,
+// eg. "$XDG_CACHE_HOME/app".
+func (f *FlagClause) DefaultHelp(help string) *FlagClause {
+	f.defaultHelp = help
+	return f
+}
+
 // DEPRECATED: Use Envar(name) instead.
 func (f *FlagClause) OverrideDefaultFromEnvar(envar string) *FlagClause {
 	return f.Envar(envar)
@@ -298,6 +388,24 @@ func (f *FlagClause) Envar(name string) *FlagClause {
 	return f
 }
 
+// EnvarFile allows the value of the flag's envar to be read from a file. If
+// the envar itself is not set but "<envar>_FILE" is, the contents of the file
+// it names are used, with any trailing newline removed. This is commonly used
+// to pass secrets to containers.
+func (f *FlagClause) EnvarFile() *FlagClause {
+	f.envarFile = true
+	return f
+}
+
+// EnvarSeparator sets the separator between multiple values in the flag's
+// envar, for flags that accept multiple values. The default is to separate
+// values with newlines. Whitespace around each value is trimmed, and the
+// separator may be escaped with a backslash.
+func (f *FlagClause) EnvarSeparator(sep string) *FlagClause {
+	f.envarSeparator = sep
+	return f
+}
+
 // NoEnvar forces environment variable defaults to be disabled for this flag.
 // Most useful in conjunction with app.DefaultEnvars().
 func (f *FlagClause) NoEnvar() *FlagClause {
@@ -314,12 +422,29 @@ func (f *FlagClause) PlaceHolder(placeholder string) *FlagClause {
 	return f
 }
 
+// Separator allows multiple values of a repeatable flag to be given in a
+// single argument, eg. with Separator(",") "--tag a,b" is equivalent to
+// "--tag a --tag b". Elements may be quoted to include the separator. The same
+// splitting applies to values from envars, configuration files and defaults.
+func (f *FlagClause) Separator(sep string) *FlagClause {
+	f.separator = sep
+	return f
+}
+
 // Hidden hides a flag from usage but still allows it to be used.
 func (f *FlagClause) Hidden() *FlagClause {
 	f.hidden = true
 	return f
 }
 
+// Secret marks the flag's value as sensitive. It is masked in help and usage
+// templates, and errors from parsing it are replaced with a generic message.
+// Arguments can not be secret, so prompted arguments are always echoed.
+func (f *FlagClause) Secret() *FlagClause {
+	f.secret = true
+	return f
+}
+
 // Required makes the flag required. You can not provide a Default() value to a Required() flag.
 func (f *FlagClause) Required() *FlagClause {
 	f.required = true
@@ -1,8 +1,11 @@
 package kingpin
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/stretchr/testify/assert"
 
@@ -390,3 +393,247 @@ func TestIsSetByUser(t *testing.T) {
 	assert.True(t, isSet)
 	assert.False(t, isSet2)
 }
+
+func TestEnvarFile(t *testing.T) {
+	path := writeTempFile(t, "secret", "hunter2\n")
+	os.Setenv("TEST_SECRET_FILE", path)
+	defer os.Unsetenv("TEST_SECRET_FILE")
+	app := newTestApp()
+	secret := app.Flag("secret", "").Envar("TEST_SECRET").EnvarFile().String()
+	other := app.Flag("other", "").Envar("TEST_SECRET").NoEnvar().String()
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", *secret)
+	assert.Equal(t, "", *other)
+	assert.Equal(t, ValueSource{Kind: SourceEnvar, Envar: "TEST_SECRET_FILE"}, app.GetFlag("secret").Source())
+	assert.Equal(t, "Secret. ($TEST_SECRET or $TEST_SECRET_FILE)", app.GetFlag("secret").Help("Secret.").Model().HelpWithEnvar())
+
+	// The envar itself takes precedence.
+	os.Setenv("TEST_SECRET", "envar")
+	defer os.Unsetenv("TEST_SECRET")
+	_, err = app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "envar", *secret)
+}
+
+func TestEnvarFiles(t *testing.T) {
+	os.Setenv("TEST_APP_SECRET_FILE", "/DEFINITELYMISSING")
+	defer os.Unsetenv("TEST_APP_SECRET_FILE")
+	app := New("test-app", "").Terminate(nil).DefaultEnvars().EnvarFiles()
+	app.Flag("secret", "").Required().String()
+	_, err := app.Parse([]string{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read $TEST_APP_SECRET_FILE")
+}
+
+func TestSecretFlag(t *testing.T) {
+	app := newTestApp()
+	f := app.Flag("pin", "").Secret().Default("1234").Int()
+	_, err := app.Parse([]string{"--pin=hunter2"})
+	assert.EqualError(t, err, "invalid value for '--pin'")
+
+	_, err = app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1234, *f)
+	model := app.GetFlag("pin").Model()
+	assert.Equal(t, secretMask, model.String())
+	assert.Equal(t, []string{secretMask}, model.Default)
+	assert.Equal(t, "PIN", model.FormatPlaceHolder())
+}
+
+func TestSecretFlagTemplateValue(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestApp().UsageWriter(&buf)
+	app.Flag("pin", "").Secret().Int()
+	app.Flag("user", "").String()
+	context, err := app.ParseContext([]string{"--pin=4321", "--user=bob"})
+	assert.NoError(t, err)
+	_, err = app.setValues(context)
+	assert.NoError(t, err)
+	err = app.UsageForContextWithTemplate(context, 0, `{{range .Context.Flags}}{{if not .Hidden}}{{.Name}}={{.Value}} {{end}}{{end}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "help=false pin="+secretMask+" user=bob ", buf.String())
+	assert.Equal(t, secretMask, app.GetFlag("pin").Model().Value.String())
+	assert.Equal(t, 4321, app.GetFlag("pin").Model().Value.(Getter).Get())
+}
+
+func TestSecretFlagFromEnvar(t *testing.T) {
+	os.Setenv("TEST_SECRET_PIN", "hunter2")
+	defer os.Unsetenv("TEST_SECRET_PIN")
+	app := newTestApp()
+	app.Flag("pin", "").Envar("TEST_SECRET_PIN").Secret().Int()
+	_, err := app.Parse([]string{})
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "hunter2")
+}
+
+func TestFlagEnvarSeparator(t *testing.T) {
+	os.Setenv("TEST_HOSTS", `a:1, b:2,,c\,d:3 `)
+	defer os.Unsetenv("TEST_HOSTS")
+	app := newTestApp()
+	hosts := app.Flag("hosts", "Hosts.").Envar("TEST_HOSTS").EnvarSeparator(",").Strings()
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a:1", "b:2", "c,d:3"}, *hosts)
+	assert.Equal(t, "Hosts. ($TEST_HOSTS, comma-separated)", app.GetFlag("hosts").Model().HelpWithEnvar())
+
+	t.Setenv("TEST_HOSTS", "O'Brien,x")
+	*hosts = nil
+	_, err = app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"O'Brien", "x"}, *hosts)
+}
+
+func TestSplitList(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, splitList("a, b ,c", ","))
+	assert.Equal(t, []string{"a;b", `c\`}, splitList(`a\;b;c\\`, ";"))
+	assert.Equal(t, []string{"a", "b"}, splitList("a::b", "::"))
+	assert.Equal(t, []string{}, splitList(" , ", ","))
+	assert.Equal(t, []string{"a,b", " c ", "", `d"e`}, splitList(`"a,b", ' c ', "", d\"e`, ","))
+	assert.Equal(t, []string{"it's", "x"}, splitList(`"it's";x`, ";"))
+	assert.Equal(t, []string{"it's", "ok"}, splitList(`it's,ok`, ","))
+	assert.Equal(t, []string{"O'Brien", "x"}, splitList(`O'Brien,x`, ","))
+	assert.Equal(t, []string{`say "hi"`, "x"}, splitList(`say "hi",x`, ","))
+	// Unterminated quotes are kept literally.
+	assert.Equal(t, []string{`'a`, "b"}, splitList(`'a,b`, ","))
+	assert.Equal(t, []string{"a", `"b`}, splitList(`a, "b`, ","))
+}
+
+func TestFlagSeparator(t *testing.T) {
+	t.Setenv("TEST_SEPARATOR_TAGS", "x,y")
+	app := newTestApp()
+	tags := app.Flag("tag", "").Separator(",").Strings()
+	levels := app.Flag("level", "").Separator(",").Enums("debug", "info")
+	envTags := app.Flag("env-tag", "").Envar("TEST_SEPARATOR_TAGS").Separator(",").Strings()
+	defaults := app.Flag("default", "").Separator(",").Default("1,2", "3").Ints()
+	_, err := app.Parse([]string{"--tag", `a,"b,c"`, "--tag=d", "--level=debug,info"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b,c", "d"}, *tags)
+	assert.Equal(t, []string{"debug", "info"}, *levels)
+	assert.Equal(t, []string{"x", "y"}, *envTags)
+	assert.Equal(t, []int{1, 2, 3}, *defaults)
+
+	*tags = nil
+	_, err = app.Parse([]string{"--tag=it's,ok"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"it's", "ok"}, *tags)
+
+	_, err = app.Parse([]string{"--level=debug,trace"})
+	assert.Error(t, err)
+
+	app = newTestApp()
+	app.Flag("name", "").Separator(",").String()
+	_, err = app.Parse([]string{})
+	assert.EqualError(t, err, "Separator() set on '--name', which does not accept multiple values")
+}
+
+func TestApplicationSeparator(t *testing.T) {
+	app := newTestApp().Separator(";")
+	tags := app.Flag("tag", "").Strings()
+	name := app.Flag("name", "").String()
+	_, err := app.Parse([]string{"--tag=a;b", "--name=c;d"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, *tags)
+	assert.Equal(t, "c;d", *name)
+}
+
+func TestFlagAllowFromFile(t *testing.T) {
+	path := writeTempFile(t, "cert.pem", "-----BEGIN CERTIFICATE-----\n")
+	app := newTestApp()
+	cert := app.Flag("cert", "").Short('c').AllowFromFile().String()
+	data := app.Flag("data", "").FromFilePrefix("file:").String()
+	name := app.Flag("name", "").String()
+	_, err := app.Parse([]string{"--cert=@" + path, "--data=file:" + path, "--name=@literal"})
+	assert.NoError(t, err)
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----", *cert)
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----", *data)
+	assert.Equal(t, "@literal", *name)
+
+	// Not expanded as an argument file.
+	_, err = app.Parse([]string{"--cert", "@" + path})
+	assert.NoError(t, err)
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----", *cert)
+	_, err = app.Parse([]string{"-c", "@" + path})
+	assert.NoError(t, err)
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----", *cert)
+
+	_, err = app.Parse([]string{"--cert=@" + path + ".missing"})
+	assert.Error(t, err)
+
+	// Only one trailing newline is removed.
+	_, err = app.Parse([]string{"--cert=@" + writeTempFile(t, "token.txt", "secret\r\n\n")})
+	assert.NoError(t, err)
+	assert.Equal(t, "secret\r\n", *cert)
+}
+
+func TestFlagAllowFromFileStdin(t *testing.T) {
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	os.Stdin, _ = os.Open(writeTempFile(t, "stdin", "payload"))
+
+	app := newTestApp()
+	data := app.Flag("data", "").AllowFromFile().String()
+	app.Flag("other", "").AllowFromFile().String()
+	_, err := app.Parse([]string{"--data=@-"})
+	assert.NoError(t, err)
+	assert.Equal(t, "payload", *data)
+
+	_, err = app.Parse([]string{"--data=@-", "--other=@-"})
+	assert.EqualError(t, err, "flag 'other': stdin can only be read once")
+}
+
+func TestFlagDefaultFunc(t *testing.T) {
+	app := newTestApp()
+	calls := 0
+	host := app.Flag("host", "").DefaultFunc(func() (string, error) {
+		calls++
+		return "db.internal", nil
+	}).String()
+	_, err := app.Parse([]string{"--host=localhost"})
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", *host)
+	assert.Equal(t, 0, calls)
+
+	_, err = app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "db.internal", *host)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, ValueSource{Kind: SourceDefault}, app.GetFlag("host").Source())
+
+	app = newTestApp()
+	app.Flag("host", "").DefaultFunc(func() (string, error) { return "", fmt.Errorf("no host") }).String()
+	_, err = app.Parse([]string{})
+	assert.EqualError(t, err, "default for '--host': no host")
+
+	app = newTestApp()
+	app.Flag("host", "").Required().DefaultFunc(os.Hostname).String()
+	_, err = app.Parse([]string{})
+	assert.EqualError(t, err, "required flag '--host' with default value that will never be used")
+}
+
+func TestFlagDefaultExpansion(t *testing.T) {
+	t.Setenv("TEST_CACHE_HOME", "/var/cache")
+	homeDir, err := os.UserHomeDir()
+	assert.NoError(t, err)
+	app := newTestApp()
+	cache := app.Flag("cache", "").Default("${TEST_CACHE_HOME}/app").ExpandDefault().String()
+	config := app.Flag("config", "").Default("~/.app").ExpandDefault().String()
+	literal := app.Flag("literal", "").Default(`\${TEST_CACHE_HOME}`).ExpandDefault().String()
+	price := app.Flag("price", "").Default("price: ${TEST_CACHE_HOME}").String()
+	home := app.Flag("home", "").Default("~/.app").String()
+	_, err = app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/cache/app", *cache)
+	assert.Equal(t, filepath.Join(homeDir, ".app"), *config)
+	assert.Equal(t, "${TEST_CACHE_HOME}", *literal)
+	// Defaults are literal unless ExpandDefault() is used.
+	assert.Equal(t, "price: ${TEST_CACHE_HOME}", *price)
+	assert.Equal(t, "~/.app", *home)
+	assert.Equal(t, `--cache="${TEST_CACHE_HOME}/app"`, formatFlag(false, app.GetFlag("cache").Model()))
+}
+
+func TestFlagDefaultHelp(t *testing.T) {
+	app := newTestApp()
+	app.Flag("cache", "").DefaultHelp("$XDG_CACHE_HOME/app").DefaultFunc(os.UserCacheDir).String()
+	assert.Equal(t, "--cache=$XDG_CACHE_HOME/app", formatFlag(false, app.GetFlag("cache").Model()))
+}
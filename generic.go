@@ -0,0 +1,260 @@
+package kingpin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FlagOf sets the value of a flag or argument to be parsed by parse, returning
+// a pointer to the parsed value.
+//
+//	addr := kingpin.FlagOf(app.Flag("addr", "Address to listen on."), netip.ParseAddr)
+func FlagOf[T any](s Settings, parse func(string) (T, error)) *T {
+	target := new(T)
+	FlagOfVar(s, target, parse)
+	return target
+}
+
+// FlagOfVar is like FlagOf but stores the parsed value in target.
+func FlagOfVar[T any](s Settings, target *T, parse func(string) (T, error)) {
+	s.SetValue(&genericValue[T]{target: target, parse: parse})
+}
+
+// SliceOf sets the value of a repeatable flag or argument to be parsed by
+// parse, returning a pointer to the accumulated values.
+func SliceOf[T any](s Settings, parse func(string) (T, error)) *[]T {
+	target := new([]T)
+	SliceOfVar(s, target, parse)
+	return target
+}
+
+// SliceOfVar is like SliceOf but stores the parsed values in target.
+func SliceOfVar[T any](s Settings, target *[]T, parse func(string) (T, error)) {
+	s.SetValue(&genericSliceValue[T]{target: target, parse: parse})
+}
+
+// MapOf sets the value of a repeatable flag or argument to KEY=VALUE pairs
+// parsed by parseKey and parseValue, returning a pointer to the map.
+func MapOf[K comparable, V any](s Settings, parseKey func(string) (K, error), parseValue func(string) (V, error), options ...MapOption) *map[K]V {
+	target := map[K]V{}
+	MapOfVar(s, &target, parseKey, parseValue, options...)
+	return &target
+}
+
+// MapOfVar is like MapOf but stores the parsed pairs in target.
+func MapOfVar[K comparable, V any](s Settings, target *map[K]V, parseKey func(string) (K, error), parseValue func(string) (V, error), options ...MapOption) {
+	if *target == nil {
+		*target = map[K]V{}
+	}
+	value := &genericMapValue[K, V]{target: target, parseKey: parseKey, parseValue: parseValue}
+	for _, option := range options {
+		option(&value.mapOptions)
+	}
+	s.SetValue(value)
+}
+
+// A MapOption configures the parsing of map values.
+type MapOption func(*mapOptions)
+
+type mapOptions struct {
+	separator string // Between keys and values, defaults to = or :.
+	batch     string // Between pairs in a single value, if any.
+	unique    bool
+}
+
+// MapSeparator sets the separator between keys and values, instead of the
+// first = or :.
+func MapSeparator(separator string) MapOption {
+	return func(o *mapOptions) { o.separator = separator }
+}
+
+// MapBatch accepts several KEY=VALUE pairs in a single value, separated by
+// separator, eg. MapBatch(",") for "a=1,b=2". A value is only split if every
+// part of it is a pair, so "a=x,y" still sets a to "x,y".
+func MapBatch(separator string) MapOption {
+	return func(o *mapOptions) { o.batch = separator }
+}
+
+// MapUniqueKeys rejects keys that are given more than once.
+func MapUniqueKeys() MapOption {
+	return func(o *mapOptions) { o.unique = true }
+}
+
+// Split a KEY=VALUE pair.
+func (o *mapOptions) split(value string) ([]string, bool) {
+	var parts []string
+	if o.separator != "" {
+		parts = strings.SplitN(value, o.separator, 2)
+	} else {
+		parts = stringMapRegex.Split(value, 2)
+	}
+	return parts, len(parts) == 2
+}
+
+func (o *mapOptions) placeHolder() string {
+	if o.separator != "" {
+		return "KEY" + o.separator + "VALUE"
+	}
+	return "KEY=VALUE"
+}
+
+type genericValue[T any] struct {
+	target *T
+	parse  func(string) (T, error)
+}
+
+func (g *genericValue[T]) Set(value string) error {
+	v, err := g.parse(value)
+	if err != nil {
+		return err
+	}
+	*g.target = v
+	return nil
+}
+
+func (g *genericValue[T]) Get() interface{} { return *g.target }
+
+func (g *genericValue[T]) String() string { return fmt.Sprint(*g.target) }
+
+// Treat FlagOf[bool] as a boolean flag.
+func (g *genericValue[T]) IsBoolFlag() bool {
+	_, ok := interface{}(g.target).(*bool)
+	return ok
+}
+
+type genericSliceValue[T any] struct {
+	target *[]T
+	parse  func(string) (T, error)
+}
+
+func (g *genericSliceValue[T]) Set(value string) error {
+	v, err := g.parse(value)
+	if err != nil {
+		return err
+	}
+	*g.target = append(*g.target, v)
+	return nil
+}
+
+func (g *genericSliceValue[T]) Get() interface{} { return *g.target }
+
+func (g *genericSliceValue[T]) String() string {
+	out := []string{}
+	for _, v := range *g.target {
+		out = append(out, fmt.Sprint(v))
+	}
+	return strings.Join(out, ",")
+}
+
+func (g *genericSliceValue[T]) IsCumulative() bool { return true }
+
+type genericMapValue[K comparable, V any] struct {
+	mapOptions
+	target     *map[K]V
+	parseKey   func(string) (K, error)
+	parseValue func(string) (V, error)
+}
+
+// Set a KEY=VALUE pair, or a batch of them if enabled with MapBatch.
+func (g *genericMapValue[K, V]) Set(value string) error {
+	if g.batch == "" {
+		return g.set(value)
+	}
+	pairs := strings.Split(value, g.batch)
+	for _, pair := range pairs {
+		if _, ok := g.split(pair); !ok {
+			// Not a batch, the value itself may contain commas.
+			pairs = []string{value}
+			break
+		}
+	}
+	for _, pair := range pairs {
+		if err := g.set(pair); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *genericMapValue[K, V]) set(value string) error {
+	parts, ok := g.split(value)
+	if !ok {
+		return fmt.Errorf("expected %s got '%s'", g.placeHolder(), value)
+	}
+	k, err := g.parseKey(parts[0])
+	if err != nil {
+		return err
+	}
+	if _, ok := (*g.target)[k]; ok && g.unique {
+		return fmt.Errorf("duplicate key '%s'", parts[0])
+	}
+	v, err := g.parseValue(parts[1])
+	if err != nil {
+		return err
+	}
+	(*g.target)[k] = v
+	return nil
+}
+
+func (g *genericMapValue[K, V]) Get() interface{} { return *g.target }
+
+func (g *genericMapValue[K, V]) String() string { return fmt.Sprint(*g.target) }
+
+func (g *genericMapValue[K, V]) IsCumulative() bool { return true }
+
+func (g *genericMapValue[K, V]) PlaceHolder() string { return g.placeHolder() }
+
+// EnumOf sets the value of a flag or argument to one of options, mapping each
+// option name to a Go value.
+//
+//	level := kingpin.EnumOf(app.Flag("level", "Log level."), map[string]slog.Level{
+//		"debug": slog.LevelDebug,
+//		"info":  slog.LevelInfo,
+//	})
+func EnumOf[T comparable](s Settings, options map[string]T) *T {
+	target := new(T)
+	EnumOfVar(s, target, options)
+	return target
+}
+
+// EnumOfVar is like EnumOf but stores the value in target.
+func EnumOfVar[T comparable](s Settings, target *T, options map[string]T) {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s.SetValue(&genericEnumValue[T]{
+		enumOptions: enumOptions{options: names},
+		target:      target,
+		values:      options,
+	})
+}
+
+type genericEnumValue[T comparable] struct {
+	enumOptions
+	target *T
+	values map[string]T
+}
+
+func (g *genericEnumValue[T]) Set(value string) error {
+	option, err := g.match(value)
+	if err != nil {
+		return err
+	}
+	*g.target = g.values[option]
+	return nil
+}
+
+func (g *genericEnumValue[T]) Get() interface{} { return *g.target }
+
+// String returns the name of the current value, if it has one.
+func (g *genericEnumValue[T]) String() string {
+	for _, option := range g.options {
+		if g.values[option] == *g.target {
+			return option
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package kingpin
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagOf(t *testing.T) {
+	app := newTestApp()
+	addr := FlagOf(app.Flag("addr", "").Default("127.0.0.1"), netip.ParseAddr)
+	debug := FlagOf(app.Flag("debug", ""), strconv.ParseBool)
+	name := FlagOf(app.Arg("name", ""), func(s string) (string, error) { return strings.ToUpper(s), nil })
+	_, err := app.Parse([]string{"--debug", "harry"})
+	assert.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("127.0.0.1"), *addr)
+	assert.True(t, *debug)
+	assert.Equal(t, "HARRY", *name)
+
+	_, err = app.Parse([]string{"--addr=nope"})
+	assert.Error(t, err)
+}
+
+func TestSliceOf(t *testing.T) {
+	app := newTestApp()
+	addrs := SliceOf(app.Flag("addr", ""), netip.ParseAddr)
+	ports := SliceOf(app.Arg("port", ""), strconv.Atoi)
+	_, err := app.Parse([]string{"--addr=::1", "--addr=10.0.0.1", "80", "443"})
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("::1"), netip.MustParseAddr("10.0.0.1")}, *addrs)
+	assert.Equal(t, []int{80, 443}, *ports)
+	assert.Equal(t, "::1,10.0.0.1", app.GetFlag("addr").value.String())
+}
+
+func TestMapOf(t *testing.T) {
+	app := newTestApp()
+	parseString := func(s string) (string, error) { return s, nil }
+	weights := MapOf(app.Flag("weight", ""), parseString, strconv.Atoi)
+	_, err := app.Parse([]string{"--weight=a=1", "--weight=b=2"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, *weights)
+
+	_, err = app.Parse([]string{"--weight=a"})
+	assert.EqualError(t, err, "expected KEY=VALUE got 'a'")
+}
+
+func TestEnumOf(t *testing.T) {
+	app := newTestApp()
+	type level int
+	options := map[string]level{"debug": -4, "info": 0, "warn": 4}
+	l := EnumOf(app.Flag("level", "").IgnoreCase(), options)
+	_, err := app.Parse([]string{"--level=WARN"})
+	assert.NoError(t, err)
+	assert.Equal(t, level(4), *l)
+	assert.Equal(t, "warn", app.GetFlag("level").value.String())
+	assert.Equal(t, []string{"debug", "info", "warn"}, app.GetFlag("level").resolveCompletions())
+
+	_, err = app.Parse([]string{"--level=trace"})
+	assert.EqualError(t, err, "enum value must be one of debug,info,warn, got 'trace'")
+}
@@ -10,12 +10,15 @@ import (
 
 var (
 	ignoreInCount = map[string]bool{
-		"help":                   true,
-		"help-long":              true,
-		"help-man":               true,
-		"completion-bash":        true,
-		"completion-script-bash": true,
-		"completion-script-zsh":  true,
+		"help":                    true,
+		"help-long":               true,
+		"help-man":                true,
+		"help-env":                true,
+		"completion-bash":         true,
+		"completion-script-bash":  true,
+		"completion-script-zsh":   true,
+		"completion-script-fish":  true,
+		"completion-descriptions": true,
 	}
 )
 
@@ -48,26 +51,35 @@ func (f *FlagGroupModel) FlagSummary() string {
 }
 
 type FlagModel struct {
-	Name        string
-	Help        string
-	Short       rune
-	Default     []string
-	Envar       string
-	PlaceHolder string
-	Required    bool
-	Hidden      bool
-	Value       Value
+	Name           string
+	Help           string
+	Short          rune
+	Default        []string
+	DefaultHelp    string // Describes the default, if set.
+	Envar          string
+	EnvarFile      bool
+	EnvarSeparator string
+	Separator      string
+	PlaceHolder    string
+	Required       bool
+	Hidden         bool
+	Secret         bool
+	Value          Value // Masked in String() if Secret.
+	Source         ValueSource
 }
 
 func (f *FlagModel) String() string {
 	if f.Value == nil {
 		return ""
 	}
-	return f.Value.String()
+	if s := f.Value.String(); !f.Secret || s == "" {
+		return s
+	}
+	return secretMask
 }
 
 func (f *FlagModel) IsBoolFlag() bool {
-	if fl, ok := f.Value.(boolFlag); ok {
+	if fl, ok := unmasked(f.Value).(boolFlag); ok {
 		return fl.IsBoolFlag()
 	}
 	return false
@@ -77,24 +89,86 @@ func (f *FlagModel) FormatPlaceHolder() string {
 	if f.PlaceHolder != "" {
 		return f.PlaceHolder
 	}
-	if len(f.Default) > 0 {
+	if f.DefaultHelp != "" {
+		return f.DefaultHelp
+	}
+	if len(f.Default) > 0 && !f.Secret {
 		ellipsis := ""
 		if len(f.Default) > 1 {
 			ellipsis = "..."
 		}
-		if _, ok := f.Value.(*stringValue); ok {
+		if _, ok := unmasked(f.Value).(*stringValue); ok {
 			return strconv.Quote(f.Default[0]) + ellipsis
 		}
 		return f.Default[0] + ellipsis
 	}
+	if v, ok := unmasked(f.Value).(placeHolderValue); ok && v.PlaceHolder() != "" {
+		return v.PlaceHolder()
+	}
 	return strings.ToUpper(f.Name)
 }
 
 func (f *FlagModel) HelpWithEnvar() string {
-	if f.Envar == "" {
-		return f.Help
+	help := f.Help
+	if f.Envar != "" {
+		help = fmt.Sprintf("%s (%s)", help, formatEnvar(f.Envar, f.EnvarFile, f.EnvarSeparator))
 	}
-	return fmt.Sprintf("%s ($%s)", f.Help, f.Envar)
+	return help + formatEnumOptions(f.EnumOptions())
+}
+
+// EnumOptions returns the options of an enum or KeyValueOptions flag.
+func (f *FlagModel) EnumOptions() []*EnumOptionModel {
+	return enumOptionModels(unmasked(f.Value))
+}
+
+// EnumOptionModel is an option of an enum or KeyValueOptions flag or
+// argument.
+type EnumOptionModel struct {
+	Name string
+	Help string
+}
+
+// Implemented by values with options to list in help, such as enums.
+type describedOptionsValue interface {
+	describeOptions() []*EnumOptionModel
+}
+
+func enumOptionModels(value Value) []*EnumOptionModel {
+	if v, ok := value.(describedOptionsValue); ok {
+		return v.describeOptions()
+	}
+	return nil
+}
+
+// Format described options as an indented block to follow the help.
+func formatEnumOptions(options []*EnumOptionModel) string {
+	width := 0
+	described := false
+	for _, option := range options {
+		if len(option.Name) > width {
+			width = len(option.Name)
+		}
+		described = described || option.Help != ""
+	}
+	if !described {
+		return ""
+	}
+	out := "\n"
+	for _, option := range options {
+		out += fmt.Sprintf("\n  %-*s  %s", width, option.Name, option.Help)
+	}
+	return out
+}
+
+func formatEnvar(envar string, envarFile bool, separator string) string {
+	out := "$" + envar
+	if envarFile {
+		out = fmt.Sprintf("$%s or $%s_FILE", envar, envar)
+	}
+	if separator != "" {
+		out += ", " + formatSeparator(separator)
+	}
+	return out
 }
 
 type ArgGroupModel struct {
@@ -122,21 +196,31 @@ func (a *ArgGroupModel) ArgSummary() string {
 }
 
 func (a *ArgModel) HelpWithEnvar() string {
-	if a.Envar == "" {
-		return a.Help
+	help := a.Help
+	if a.Envar != "" {
+		help = fmt.Sprintf("%s (%s)", help, formatEnvar(a.Envar, a.EnvarFile, a.EnvarSeparator))
 	}
-	return fmt.Sprintf("%s ($%s)", a.Help, a.Envar)
+	return help + formatEnumOptions(a.EnumOptions())
+}
+
+// EnumOptions returns the options of an enum or KeyValueOptions argument.
+func (a *ArgModel) EnumOptions() []*EnumOptionModel {
+	return enumOptionModels(a.Value)
 }
 
 type ArgModel struct {
-	Name        string
-	Help        string
-	Default     []string
-	Envar       string
-	PlaceHolder string
-	Required    bool
-	Hidden      bool
-	Value       Value
+	Name           string
+	Help           string
+	Default        []string
+	DefaultHelp    string // Describes the default, if set.
+	Envar          string
+	EnvarFile      bool
+	EnvarSeparator string
+	PlaceHolder    string
+	Required       bool
+	Hidden         bool
+	Value          Value
+	Source         ValueSource
 }
 
 func (a *ArgModel) String() string {
@@ -187,6 +271,87 @@ type ApplicationModel struct {
 	*ArgGroupModel
 	*CmdGroupModel
 	*FlagGroupModel
+	lookupEnvar func(string) (string, bool)
+}
+
+// Envars returns all environment variables consulted by visible flags and
+// arguments of the application and its commands. Envars of hidden flags,
+// arguments and commands are omitted, as they would reveal them, although
+// they are still read while parsing.
+func (a *ApplicationModel) Envars() []*EnvarModel {
+	out := []*EnvarModel{}
+	var walk func(command string, flags *FlagGroupModel, args *ArgGroupModel, cmds *CmdGroupModel)
+	walk = func(command string, flags *FlagGroupModel, args *ArgGroupModel, cmds *CmdGroupModel) {
+		for _, flag := range flags.Flags {
+			if !flag.Hidden && flag.Envar != "" {
+				out = append(out, a.envarModels("--"+flag.Name, command, flag.Help, defaultsHelp(flag.Default, flag.DefaultHelp), flag.Envar, flag.EnvarFile, flag.Secret)...)
+			}
+		}
+		for _, arg := range args.Args {
+			if !arg.Hidden && arg.Envar != "" {
+				out = append(out, a.envarModels("<"+arg.Name+">", command, arg.Help, defaultsHelp(arg.Default, arg.DefaultHelp), arg.Envar, arg.EnvarFile, false)...)
+			}
+		}
+		for _, cmd := range cmds.Commands {
+			if !cmd.Hidden {
+				walk(cmd.FullCommand, cmd.FlagGroupModel, cmd.ArgGroupModel, cmd.CmdGroupModel)
+			}
+		}
+	}
+	walk("", a.FlagGroupModel, a.ArgGroupModel, a.CmdGroupModel)
+	return out
+}
+
+// The defaults to show in help.
+func defaultsHelp(defaults []string, help string) []string {
+	if help != "" {
+		return []string{help}
+	}
+	return defaults
+}
+
+func (a *ApplicationModel) envarModels(clause, command, help string, defaults []string, envar string, envarFile, secret bool) []*EnvarModel {
+	names := []string{envar}
+	if envarFile {
+		names = append(names, envar+"_FILE")
+	}
+	out := []*EnvarModel{}
+	for i, name := range names {
+		value, set := a.lookupEnvar(name)
+		if secret && i == 0 && value != "" {
+			value = secretMask
+		}
+		out = append(out, &EnvarModel{
+			Name:    name,
+			Clause:  clause,
+			Command: command,
+			Help:    help,
+			Default: defaults,
+			Set:     set,
+			Value:   value,
+		})
+	}
+	return out
+}
+
+// EnvarModel describes an environment variable and the flag or argument it
+// provides the value for.
+type EnvarModel struct {
+	Name string
+	// Clause is the flag ("--name") or argument ("<name>") the envar is for.
+	Clause string
+	// Command is the full command the clause belongs to, if any.
+	Command string
+	Help    string
+	Default []string
+	// Set is true if the envar is currently set, in which case Value is its
+	// value (masked for secrets).
+	Set   bool
+	Value string
+}
+
+func (e *EnvarModel) String() string {
+	return e.Name
 }
 
 func (a *Application) Model() *ApplicationModel {
@@ -198,6 +363,7 @@ func (a *Application) Model() *ApplicationModel {
 		FlagGroupModel: a.flagGroup.Model(),
 		ArgGroupModel:  a.argGroup.Model(),
 		CmdGroupModel:  a.cmdGroup.Model(),
+		lookupEnvar:    a.lookupEnvar,
 	}
 }
 
@@ -211,14 +377,18 @@ func (a *argGroup) Model() *ArgGroupModel {
 
 func (a *ArgClause) Model() *ArgModel {
 	return &ArgModel{
-		Name:        a.name,
-		Help:        a.help,
-		Default:     a.defaultValues,
-		Envar:       a.envar,
-		PlaceHolder: a.placeholder,
-		Required:    a.required,
-		Hidden:      a.hidden,
-		Value:       a.value,
+		Name:           a.name,
+		Help:           a.help,
+		Default:        a.defaultValues,
+		DefaultHelp:    a.defaultHelp,
+		Envar:          a.envar,
+		EnvarFile:      a.envarFile,
+		EnvarSeparator: a.envarSeparator,
+		PlaceHolder:    a.placeholder,
+		Required:       a.required,
+		Hidden:         a.hidden,
+		Value:          a.value,
+		Source:         a.source,
 	}
 }
 
@@ -231,16 +401,27 @@ func (f *flagGroup) Model() *FlagGroupModel {
 }
 
 func (f *FlagClause) Model() *FlagModel {
+	defaultValues, value := f.defaultValues, f.value
+	if f.secret {
+		defaultValues = maskSecrets(defaultValues)
+		value = maskedValue{value, f.name}
+	}
 	return &FlagModel{
-		Name:        f.name,
-		Help:        f.help,
-		Short:       rune(f.shorthand),
-		Default:     f.defaultValues,
-		Envar:       f.envar,
-		PlaceHolder: f.placeholder,
-		Required:    f.required,
-		Hidden:      f.hidden,
-		Value:       f.value,
+		Name:           f.name,
+		Help:           f.help,
+		Short:          rune(f.shorthand),
+		Default:        defaultValues,
+		DefaultHelp:    f.defaultHelp,
+		Envar:          f.envar,
+		EnvarFile:      f.envarFile,
+		EnvarSeparator: f.envarSeparator,
+		Separator:      f.separator,
+		PlaceHolder:    f.placeholder,
+		Required:       f.required,
+		Hidden:         f.hidden,
+		Secret:         f.secret,
+		Value:          value,
+		Source:         f.source,
 	}
 }
 
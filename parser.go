@@ -3,6 +3,7 @@ package kingpin
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"unicode/utf8"
@@ -80,6 +81,7 @@ type ParseElement struct {
 	Clause interface{}
 	// Value is corresponding value for an ArgClause or FlagClause (if any).
 	Value *string
+	index int // Index of the command-line token the element was parsed from.
 }
 
 // ParseContext holds the current context of the parser. When passed to
@@ -97,6 +99,10 @@ type ParseContext struct {
 	flags           *flagGroup
 	arguments       *argGroup
 	argumenti       int // Cursor into arguments
+	configFile      string
+	config          map[interface{}]*configValue // Flag and argument values loaded from configFile.
+	flagFromFile    bool                         // The next arg is the value of a flag that allows files.
+	stdinRead       bool                         // A flag value has been read from stdin.
 	// Flags, arguments and commands encountered and collected during parse.
 	Elements []*ParseElement
 }
@@ -172,6 +178,8 @@ func (p *ParseContext) Next() *Token {
 	}
 	arg := p.args[0]
 	p.next()
+	flagFromFile := p.flagFromFile
+	p.flagFromFile = false
 
 	if p.argsOnly {
 		return &Token{p.argi, TokenArg, arg}
@@ -186,6 +194,8 @@ func (p *ParseContext) Next() *Token {
 		token := &Token{p.argi, TokenLong, parts[0]}
 		if len(parts) == 2 {
 			p.Push(&Token{p.argi, TokenArg, parts[1]})
+		} else if flag, ok := p.flags.long[parts[0]]; ok && flag.fromFilePrefix != "" {
+			p.flagFromFile = true
 		}
 		return token
 	}
@@ -206,6 +216,8 @@ func (p *ParseContext) Next() *Token {
 			token := &Token{p.argi, TokenShort, short}
 			if len(arg) > size+1 {
 				p.Push(&Token{p.argi, TokenArg, arg[size+1:]})
+			} else if flag.fromFilePrefix != "" {
+				p.flagFromFile = true
 			}
 			return token
 		}
@@ -214,7 +226,7 @@ func (p *ParseContext) Next() *Token {
 			p.args = append([]string{"-" + arg[size+1:]}, p.args...)
 		}
 		return &Token{p.argi, TokenShort, short}
-	} else if EnableFileExpansion && strings.HasPrefix(arg, "@") {
+	} else if EnableFileExpansion && !flagFromFile && !p.argFromFile(arg) && strings.HasPrefix(arg, "@") {
 		expanded, err := ExpandArgsFromFile(arg[1:])
 		if err != nil {
 			return &Token{p.argi, TokenError, err.Error()}
@@ -230,6 +242,40 @@ func (p *ParseContext) Next() *Token {
 	return &Token{p.argi, TokenArg, arg}
 }
 
+// Whether arg is the value of the next argument, which reads it from a file.
+func (p *ParseContext) argFromFile(arg string) bool {
+	if p.argumenti >= len(p.arguments.args) {
+		return false
+	}
+	prefix := p.arguments.args[p.argumenti].fromFilePrefix
+	return prefix != "" && strings.HasPrefix(arg, prefix)
+}
+
+// Read value from a file if it starts with prefix, or from stdin if the path
+// is "-", removing one trailing newline as for <ENVAR>_FILE.
+func (p *ParseContext) readFromFile(value, prefix string) (string, error) {
+	if prefix == "" || !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+	path := value[len(prefix):]
+	if path == "-" {
+		if p.stdinRead {
+			return "", fmt.Errorf("stdin can only be read once")
+		}
+		p.stdinRead = true
+	}
+	data, err := readValueFile(path)
+	return envVarValuesTrimmer.ReplaceAllString(string(data), ""), err
+}
+
+// Read the file at path, or stdin if path is "-".
+func readValueFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
 func (p *ParseContext) Peek() *Token {
 	if len(p.peek) == 0 {
 		return p.Push(p.Next())
@@ -253,12 +299,12 @@ func (p *ParseContext) String() string {
 	return p.SelectedCommand.FullCommand()
 }
 
-func (p *ParseContext) matchedFlag(flag *FlagClause, value string) {
-	p.Elements = append(p.Elements, &ParseElement{Clause: flag, Value: &value})
+func (p *ParseContext) matchedFlag(flag *FlagClause, index int, value string) {
+	p.Elements = append(p.Elements, &ParseElement{Clause: flag, Value: &value, index: index})
 }
 
-func (p *ParseContext) matchedArg(arg *ArgClause, value string) {
-	p.Elements = append(p.Elements, &ParseElement{Clause: arg, Value: &value})
+func (p *ParseContext) matchedArg(arg *ArgClause, index int, value string) {
+	p.Elements = append(p.Elements, &ParseElement{Clause: arg, Value: &value, index: index})
 }
 
 func (p *ParseContext) matchedCmd(cmd *CmdClause) {
@@ -353,7 +399,7 @@ loop:
 				if arg == nil {
 					break loop
 				}
-				context.matchedArg(arg, token.String())
+				context.matchedArg(arg, token.Index, token.String())
 				context.Next()
 			} else {
 				break loop
@@ -386,7 +432,13 @@ loop:
 	// Set defaults for all remaining args.
 	for arg := context.nextArg(); arg != nil && !arg.consumesRemainder(); arg = context.nextArg() {
 		for _, defaultValue := range arg.defaultValues {
-			if err := arg.value.Set(defaultValue); err != nil {
+			value := defaultValue
+			if arg.expandDefault {
+				if value, err = expandDefault(defaultValue, arg.getenv); err != nil {
+					return fmt.Errorf("invalid default value '%s' for argument '%s'", defaultValue, arg.name)
+				}
+			}
+			if err = arg.value.Set(value); err != nil {
 				return fmt.Errorf("invalid default value '%s' for argument '%s'", defaultValue, arg.name)
 			}
 		}
@@ -1,12 +1,15 @@
 package kingpin
 
 import (
+	"io"
 	"net"
 	"net/url"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/alecthomas/units"
+	"github.com/xhit/go-str2duration/v2"
 )
 
 type Settings interface {
@@ -27,9 +30,23 @@ func (p *parserMixin) SetValue(value Value) {
 }
 
 // StringMap provides key=value parsing into a map.
-func (p *parserMixin) StringMap() (target *map[string]string) {
+func (p *parserMixin) StringMap(options ...MapOption) (target *map[string]string) {
 	target = &(map[string]string{})
-	p.StringMapVar(target)
+	p.StringMapVar(target, options...)
+	return
+}
+
+// IntMap provides key=value parsing into a map of ints.
+func (p *parserMixin) IntMap(options ...MapOption) (target *map[string]int) {
+	target = &(map[string]int{})
+	p.IntMapVar(target, options...)
+	return
+}
+
+// DurationMap provides key=value parsing into a map of time.Durations.
+func (p *parserMixin) DurationMap(options ...MapOption) (target *map[string]time.Duration) {
+	target = &(map[string]time.Duration{})
+	p.DurationMapVar(target, options...)
 	return
 }
 
@@ -47,6 +64,30 @@ func (p *parserMixin) Bytes() (target *units.Base2Bytes) {
 	return
 }
 
+// MetricBytes parses numeric byte units in powers of 1000. eg. 1.5MB
+func (p *parserMixin) MetricBytes() (target *units.MetricBytes) {
+	target = new(units.MetricBytes)
+	p.MetricBytesVar(target)
+	return
+}
+
+// Time parses a time in one of the given layouts (see time.Parse), defaulting
+// to RFC3339 and common variants. Times relative to the current time such as
+// "now-1h", "yesterday" or "tomorrow+2h" are also accepted.
+func (p *parserMixin) Time(layouts ...string) (target *time.Time) {
+	target = new(time.Time)
+	p.TimeVar(target, layouts...)
+	return
+}
+
+// Date parses a local date as YYYY-MM-DD, or relative to the current date such
+// as "today", "yesterday" or "now-7d".
+func (p *parserMixin) Date() (target *time.Time) {
+	target = new(time.Time)
+	p.DateVar(target)
+	return
+}
+
 // IP sets the parser to a net.IP parser.
 func (p *parserMixin) IP() (target *net.IP) {
 	target = new(net.IP)
@@ -66,6 +107,19 @@ func (p *parserMixin) TCPVar(target **net.TCPAddr) {
 	p.SetValue(newTCPAddrValue(target))
 }
 
+// HostPort parses a host:port address, adding defaultPort if the port is
+// omitted. The host is not resolved.
+func (p *parserMixin) HostPort(defaultPort uint16) (target *string) {
+	target = new(string)
+	p.HostPortVar(target, defaultPort)
+	return
+}
+
+// HostPortVar parses a host:port address. See HostPort().
+func (p *parserMixin) HostPortVar(target *string, defaultPort uint16) {
+	p.SetValue(newHostPortValue(target, defaultPort))
+}
+
 // ExistingFile sets the parser to one that requires and returns an existing file.
 func (p *parserMixin) ExistingFile() (target *string) {
 	target = new(string)
@@ -87,20 +141,85 @@ func (p *parserMixin) ExistingFileOrDir() (target *string) {
 	return
 }
 
-// File returns an os.File against an existing file.
+// NewFile sets the parser to one that requires a path that does not exist, in
+// a writable directory. A leading ~ is expanded to the home directory.
+func (p *parserMixin) NewFile() (target *string) {
+	target = new(string)
+	p.NewFileVar(target)
+	return
+}
+
+// WritableDir sets the parser to one that requires an existing, writable
+// directory.
+func (p *parserMixin) WritableDir() (target *string) {
+	target = new(string)
+	p.WritableDirVar(target)
+	return
+}
+
+// ExecutablePath sets the parser to one that requires the path of an
+// executable file, or the name of an executable in $PATH, which is resolved
+// to its path.
+func (p *parserMixin) ExecutablePath() (target *string) {
+	target = new(string)
+	p.ExecutablePathVar(target)
+	return
+}
+
+// Path sets the parser to one that returns an absolute path, expanding a
+// leading ~ to the home directory. Relative paths are resolved against the
+// working directory, or the directory of the configuration file if the path
+// was read from one.
+func (p *parserMixin) Path() (target *string) {
+	target = new(string)
+	p.PathVar(target)
+	return
+}
+
+// Glob accumulates the existing files matching glob patterns (see
+// filepath.Match), which must match at least one file each.
+func (p *parserMixin) Glob() (target *[]string) {
+	target = new([]string)
+	p.GlobVar(target)
+	return
+}
+
+// File returns an os.File against an existing file, or stdin for "-".
 func (p *parserMixin) File() (target **os.File) {
 	target = new(*os.File)
 	p.FileVar(target)
 	return
 }
 
-// File attempts to open a File with os.OpenFile(flag, perm).
+// File attempts to open a File with os.OpenFile(flag, perm). "-" is stdout if
+// flag includes os.O_WRONLY or os.O_RDWR, otherwise stdin.
 func (p *parserMixin) OpenFile(flag int, perm os.FileMode) (target **os.File) {
 	target = new(*os.File)
 	p.OpenFileVar(target, flag, perm)
 	return
 }
 
+// Input opens a file for reading, or stdin for "-".
+func (p *parserMixin) Input() (target *io.ReadCloser) {
+	target = new(io.ReadCloser)
+	p.InputVar(target)
+	return
+}
+
+// CompressedInput is like Input but transparently decompresses gzip input.
+func (p *parserMixin) CompressedInput() (target *io.ReadCloser) {
+	target = new(io.ReadCloser)
+	p.CompressedInputVar(target)
+	return
+}
+
+// Output creates a file for writing, or stdout for "-".
+func (p *parserMixin) Output() (target *io.WriteCloser) {
+	target = new(io.WriteCloser)
+	p.OutputVar(target)
+	return
+}
+
 // URL provides a valid, parsed url.URL.
 func (p *parserMixin) URL() (target **url.URL) {
 	target = new(*url.URL)
@@ -109,8 +228,31 @@ func (p *parserMixin) URL() (target **url.URL) {
 }
 
 // StringMap provides key=value parsing into a map.
-func (p *parserMixin) StringMapVar(target *map[string]string) {
-	p.SetValue(newStringMapValue(target))
+func (p *parserMixin) StringMapVar(target *map[string]string, options ...MapOption) {
+	p.SetValue(newStringMapValue(target, options...))
+}
+
+// IntMapVar provides key=value parsing into a map of ints.
+func (p *parserMixin) IntMapVar(target *map[string]int, options ...MapOption) {
+	MapOfVar(p, target, parseString, func(s string) (int, error) {
+		v, err := parseInt(s, strconv.IntSize)
+		return int(v), err
+	}, options...)
+}
+
+// DurationMapVar provides key=value parsing into a map of time.Durations.
+func (p *parserMixin) DurationMapVar(target *map[string]time.Duration, options ...MapOption) {
+	MapOfVar(p, target, parseString, str2duration.ParseDuration, options...)
+}
+
+// TimeVar parses a time in one of the given layouts. See Time().
+func (p *parserMixin) TimeVar(target *time.Time, layouts ...string) {
+	p.SetValue(newTimeValue(target, layouts...))
+}
+
+// DateVar parses a local date. See Date().
+func (p *parserMixin) DateVar(target *time.Time) {
+	p.SetValue(newDateValue(target))
 }
 
 // Float sets the parser to a float64 parser.
@@ -133,6 +275,11 @@ func (p *parserMixin) BytesVar(target *units.Base2Bytes) {
 	p.SetValue(newBytesValue(target))
 }
 
+// MetricBytesVar parses numeric byte units in powers of 1000. eg. 1.5MB
+func (p *parserMixin) MetricBytesVar(target *units.MetricBytes) {
+	p.SetValue(newMetricBytesValue(target))
+}
+
 // IP sets the parser to a net.IP parser.
 func (p *parserMixin) IPVar(target *net.IP) {
 	p.SetValue(newIPValue(target))
@@ -163,6 +310,50 @@ func (p *parserMixin) OpenFileVar(target **os.File, flag int, perm os.FileMode)
 	p.SetValue(newFileValue(target, flag, perm))
 }
 
+// NewFileVar sets the parser to one that requires a path that does not exist.
+// See NewFile().
+func (p *parserMixin) NewFileVar(target *string) {
+	p.SetValue(newNewFileValue(target))
+}
+
+// WritableDirVar sets the parser to one that requires an existing, writable
+// directory.
+func (p *parserMixin) WritableDirVar(target *string) {
+	p.SetValue(newWritableDirValue(target))
+}
+
+// ExecutablePathVar sets the parser to one that requires an executable. See
+// ExecutablePath().
+func (p *parserMixin) ExecutablePathVar(target *string) {
+	p.SetValue(newExecutablePathValue(target))
+}
+
+// PathVar sets the parser to one that returns an absolute path. See Path().
+func (p *parserMixin) PathVar(target *string) {
+	p.SetValue(newPathValue(target))
+}
+
+// GlobVar accumulates the existing files matching glob patterns. See Glob().
+func (p *parserMixin) GlobVar(target *[]string) {
+	p.SetValue(newGlobValue(target))
+}
+
+// InputVar opens a file for reading, or stdin for "-".
+func (p *parserMixin) InputVar(target *io.ReadCloser) {
+	p.SetValue(newInputValue(target, false))
+}
+
+// CompressedInputVar is like InputVar but transparently decompresses gzip
+// input.
+func (p *parserMixin) CompressedInputVar(target *io.ReadCloser) {
+	p.SetValue(newInputValue(target, true))
+}
+
+// OutputVar creates a file for writing, or stdout for "-".
+func (p *parserMixin) OutputVar(target *io.WriteCloser) {
+	p.SetValue(newOutputValue(target))
+}
+
 // URL provides a valid, parsed url.URL.
 func (p *parserMixin) URLVar(target **url.URL) {
 	p.SetValue(newURLValue(target))
@@ -204,6 +395,72 @@ func (p *parserMixin) EnumsVar(target *[]string, options ...string) {
 	p.SetValue(newEnumsFlag(target, options...))
 }
 
+// EnumDescribed allows a value from a set of options, each with a description
+// that is shown in help and completions.
+func (p *parserMixin) EnumDescribed(options map[string]string) (target *string) {
+	target = new(string)
+	p.EnumDescribedVar(target, options)
+	return
+}
+
+// EnumDescribedVar allows a value from a set of described options.
+func (p *parserMixin) EnumDescribedVar(target *string, options map[string]string) {
+	p.SetValue(&enumValue{value: target, enumOptions: newDescribedEnumOptions(options)})
+}
+
+// EnumsDescribed allows a set of values from a set of described options.
+func (p *parserMixin) EnumsDescribed(options map[string]string) (target *[]string) {
+	target = new([]string)
+	p.EnumsDescribedVar(target, options)
+	return
+}
+
+// EnumsDescribedVar allows a set of values from a set of described options.
+func (p *parserMixin) EnumsDescribedVar(target *[]string, options map[string]string) {
+	p.SetValue(&enumsValue{value: target, enumOptions: newDescribedEnumOptions(options)})
+}
+
+// Repeatable creates a new Value with factory for each value, making any
+// Value repeatable.
+//
+//	headers := app.Flag("header", "").Repeatable(func() kingpin.Value { return &HeaderValue{} })
+func (p *parserMixin) Repeatable(factory func() Value) (target *[]Value) {
+	target = new([]Value)
+	p.RepeatableVar(target, factory)
+	return
+}
+
+// RepeatableVar is like Repeatable but stores the values in target.
+func (p *parserMixin) RepeatableVar(target *[]Value, factory func() Value) {
+	p.SetValue(&repeatedValue{target: target, factory: factory})
+}
+
+// JSON unmarshals a JSON literal into target, which must be a pointer. Unknown
+// fields are rejected. The value is read from a file given as @FILE ("@-" for
+// stdin), as with AllowFromFile().
+func (p *parserMixin) JSON(target interface{}) {
+	p.SetValue(newJSONValue(target))
+}
+
+// KeyValueOptions parses "mount -o" style options, eg. "ro,uid=1000", into
+// the fields of the struct pointed to by target that are tagged with
+// `opt:"name"`, named after the field if the name is empty. Boolean options
+// may be given without a value. Fields support the "help" and "enum" tags of
+// Bind, and the help of each option is listed in the usage.
+//
+//	var opts struct {
+//		ReadOnly bool `opt:"ro" help:"Mount read-only."`
+//		UID      int  `opt:"uid" help:"Owner of all files."`
+//	}
+//	app.Flag("options", "Mount options.").Short('o').KeyValueOptions(&opts)
+func (p *parserMixin) KeyValueOptions(target interface{}) {
+	value, err := newKeyValueOptionsValue(target)
+	if err != nil {
+		panic(err)
+	}
+	p.SetValue(value)
+}
+
 // A Counter increments a number each time it is encountered.
 func (p *parserMixin) Counter() (target *int) {
 	target = new(int)
@@ -1,10 +1,15 @@
 package kingpin
 
 import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
 	"io/ioutil"
 	"net"
 	"net/url"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -96,3 +101,210 @@ func TestFloat32(t *testing.T) {
 	assert.NoError(t, err)
 	assert.InEpsilon(t, 123.45, *v, 0.001)
 }
+
+func TestParseIntMap(t *testing.T) {
+	p := parserMixin{}
+	v := p.IntMap(MapBatch(","))
+	assert.NoError(t, p.value.Set("a=1,b=0x10"))
+	assert.NoError(t, p.value.Set("c:3"))
+	assert.Equal(t, map[string]int{"a": 1, "b": 16, "c": 3}, *v)
+	assert.Error(t, p.value.Set("d=1.5"))
+	assert.EqualError(t, p.value.Set("d"), "expected KEY=VALUE got 'd'")
+}
+
+func TestParseDurationMap(t *testing.T) {
+	p := parserMixin{}
+	v := p.DurationMap(MapSeparator("="), MapBatch(","), MapUniqueKeys())
+	assert.NoError(t, p.value.Set("api=5s,db=1m"))
+	assert.Equal(t, map[string]time.Duration{"api": 5 * time.Second, "db": time.Minute}, *v)
+	assert.EqualError(t, p.value.Set("api=1s"), "duplicate key 'api'")
+	assert.EqualError(t, p.value.Set("cache:1s"), "expected KEY=VALUE got 'cache:1s'")
+}
+
+func TestParseStringMapBatch(t *testing.T) {
+	p := parserMixin{}
+	v := p.StringMap(MapBatch(","))
+	assert.NoError(t, p.value.Set("a=1,b=2"))
+	assert.NoError(t, p.value.Set("c=x,y"))
+	assert.Equal(t, map[string]string{"a": "1", "b": "2", "c": "x,y"}, *v)
+}
+
+func TestParseStringMapNoBatch(t *testing.T) {
+	app := newTestApp()
+	opts := app.Flag("opt", "").StringMap()
+	_, err := app.Parse([]string{"--opt", "JAVA_OPTS=-Xmx1g,-Dx=y", "--opt", "a=1,b=2"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"JAVA_OPTS": "-Xmx1g,-Dx=y", "a": "1,b=2"}, *opts)
+}
+
+func TestMapPlaceHolder(t *testing.T) {
+	app := newTestApp()
+	app.Flag("labels", "").StringMap()
+	app.Flag("timeouts", "").DurationMap(MapSeparator(":"))
+	assert.NoError(t, app.init())
+	assert.Equal(t, "KEY=VALUE", app.GetFlag("labels").Model().FormatPlaceHolder())
+	assert.Equal(t, "KEY:VALUE", app.GetFlag("timeouts").Model().FormatPlaceHolder())
+}
+
+func TestParseFileStdio(t *testing.T) {
+	p := parserMixin{}
+	in := p.File()
+	assert.NoError(t, p.value.Set("-"))
+	assert.Equal(t, os.Stdin, *in)
+
+	out := p.OpenFile(os.O_WRONLY|os.O_CREATE, 0600)
+	assert.NoError(t, p.value.Set("-"))
+	assert.Equal(t, os.Stdout, *out)
+	assert.Equal(t, "FILE|-", p.value.(placeHolderValue).PlaceHolder())
+}
+
+func TestParseInput(t *testing.T) {
+	path := writeTempFile(t, "input.txt", "hello")
+	p := parserMixin{}
+	r := p.Input()
+	assert.NoError(t, p.value.Set(path))
+	data, err := ioutil.ReadAll(*r)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.NoError(t, (*r).Close())
+	assert.Error(t, p.value.Set(path+".missing"))
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	gz.Write([]byte("compressed"))
+	gz.Close()
+	gzPath := writeTempFile(t, "input.txt.gz", buf.String())
+	for path, expected := range map[string]string{path: "hello", gzPath: "compressed"} {
+		p := parserMixin{}
+		r := p.CompressedInput()
+		assert.NoError(t, p.value.Set(path))
+		data, err := ioutil.ReadAll(*r)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(data))
+		assert.NoError(t, (*r).Close())
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	path := filepath.Join(filepath.Dir(writeTempFile(t, "x", "")), "output.txt")
+	p := parserMixin{}
+	w := p.Output()
+	assert.NoError(t, p.value.Set(path))
+	_, err := (*w).Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.NoError(t, (*w).Close())
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	assert.NoError(t, p.value.Set("-"))
+	assert.Equal(t, nopWriteCloser{os.Stdout}, *w)
+	assert.Equal(t, "-", p.value.String())
+}
+
+func TestParseOutputClosesPrevious(t *testing.T) {
+	dir := filepath.Dir(writeTempFile(t, "x", ""))
+	p := parserMixin{}
+	w := p.Output()
+	assert.NoError(t, p.value.Set(filepath.Join(dir, "first.txt")))
+	first := *w
+	assert.NoError(t, p.value.Set(filepath.Join(dir, "second.txt")))
+	_, err := first.Write([]byte("hello"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+	_, err = (*w).Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.NoError(t, p.value.Set("-"))
+	assert.NoError(t, p.value.Set("-"))
+}
+
+func TestParseNewFile(t *testing.T) {
+	dir := filepath.Dir(writeTempFile(t, "existing", ""))
+	p := parserMixin{}
+	v := p.NewFile()
+	assert.NoError(t, p.value.Set(filepath.Join(dir, "new")))
+	assert.Equal(t, filepath.Join(dir, "new"), *v)
+	assert.EqualError(t, p.value.Set(filepath.Join(dir, "existing")), fmt.Sprintf("path '%s' already exists", filepath.Join(dir, "existing")))
+	assert.EqualError(t, p.value.Set(filepath.Join(dir, "missing", "new")), fmt.Sprintf("directory '%s' does not exist", filepath.Join(dir, "missing")))
+}
+
+func TestParseWritableDir(t *testing.T) {
+	path := writeTempFile(t, "file", "")
+	p := parserMixin{}
+	v := p.WritableDir()
+	assert.NoError(t, p.value.Set(filepath.Dir(path)))
+	assert.Equal(t, filepath.Dir(path), *v)
+	assert.EqualError(t, p.value.Set(path), fmt.Sprintf("'%s' is not a directory", path))
+	assert.EqualError(t, p.value.Set(path+".missing"), fmt.Sprintf("path '%s.missing' does not exist", path))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "checking the directory must not create files")
+
+	readOnly := t.TempDir()
+	assert.NoError(t, os.Chmod(readOnly, 0500))
+	defer os.Chmod(readOnly, 0700)
+	if os.Geteuid() != 0 {
+		assert.EqualError(t, p.value.Set(readOnly), fmt.Sprintf("directory '%s' is not writable", readOnly))
+	}
+}
+
+func TestParseExecutablePath(t *testing.T) {
+	path := writeTempFile(t, "script", "#!/bin/sh\n")
+	p := parserMixin{}
+	v := p.ExecutablePath()
+	assert.EqualError(t, p.value.Set(path), fmt.Sprintf("'%s' is not executable", path))
+	assert.NoError(t, os.Chmod(path, 0700))
+	assert.NoError(t, p.value.Set(path))
+	assert.Equal(t, path, *v)
+	t.Setenv("PATH", filepath.Dir(path))
+	assert.NoError(t, p.value.Set("script"))
+	assert.Equal(t, path, *v)
+	assert.EqualError(t, p.value.Set("definitely-missing"), "executable 'definitely-missing' not found in $PATH")
+}
+
+func TestParsePath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	assert.NoError(t, err)
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	p := parserMixin{}
+	v := p.Path()
+	assert.NoError(t, p.value.Set("~/cache"))
+	assert.Equal(t, filepath.Join(home, "cache"), *v)
+	assert.NoError(t, p.value.Set("data"))
+	assert.Equal(t, filepath.Join(wd, "data"), *v)
+}
+
+func TestPathRelativeToConfigFile(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"cache": "cache", "include": ["a", "/b"]}`)
+	app := newTestApp().ConfigFile(path)
+	cache := app.Flag("cache", "").Path()
+	include := app.Flag("include", "").Paths()
+	other := app.Flag("other", "").Path()
+	_, err := app.Parse([]string{"--other=other"})
+	assert.NoError(t, err)
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	dir := filepath.Dir(path)
+	assert.Equal(t, filepath.Join(dir, "cache"), *cache)
+	assert.Equal(t, []string{filepath.Join(dir, "a"), "/b"}, *include)
+	assert.Equal(t, filepath.Join(wd, "other"), *other)
+}
+
+func TestParseGlob(t *testing.T) {
+	a := writeTempFile(t, "a.txt", "")
+	dir := filepath.Dir(a)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b.txt"), nil, 0600))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "c.txt"), 0700))
+	app := newTestApp()
+	files := app.Arg("files", "").Glob()
+	_, err := app.Parse([]string{filepath.Join(dir, "*.txt"), a})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{a, filepath.Join(dir, "b.txt"), a}, *files)
+
+	p := parserMixin{}
+	p.Glob()
+	assert.EqualError(t, p.value.Set(filepath.Join(dir, "*.md")), fmt.Sprintf("pattern '%s' matched no files", filepath.Join(dir, "*.md")))
+	assert.EqualError(t, p.value.Set(filepath.Join(dir, "d.txt")), fmt.Sprintf("path '%s' does not exist", filepath.Join(dir, "d.txt")))
+	assert.EqualError(t, p.value.Set(filepath.Join(dir, "c.txt")), fmt.Sprintf("'%s' is a directory", filepath.Join(dir, "c.txt")))
+}
@@ -0,0 +1,119 @@
+package kingpin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// A Prompter interactively reads values for required flags and arguments
+// that were not provided.
+type Prompter interface {
+	// IsTerminal returns true if the user can be prompted.
+	IsTerminal() bool
+	// Prompt displays text and reads a single line of input. If options is
+	// not empty the input must be one of them. Input must not be echoed if
+	// secret is true.
+	Prompt(text string, options []string, secret bool) (string, error)
+}
+
+type terminalPrompter struct {
+	in     *os.File
+	out    io.Writer
+	reader *bufio.Reader
+}
+
+// NewTerminalPrompter creates a Prompter that reads from in, which must be a
+// terminal, and writes prompts to out.
+func NewTerminalPrompter(in *os.File, out io.Writer) Prompter {
+	return &terminalPrompter{in: in, out: out, reader: bufio.NewReader(in)}
+}
+
+func (t *terminalPrompter) IsTerminal() bool {
+	return isTerminal(t.in.Fd())
+}
+
+func (t *terminalPrompter) Prompt(text string, options []string, secret bool) (string, error) {
+	if len(options) > 0 {
+		text = fmt.Sprintf("%s (%s)", text, strings.Join(options, ", "))
+	}
+	fmt.Fprintf(t.out, "%s: ", text)
+	if secret {
+		restore, err := disableEcho(t.in.Fd())
+		if err != nil {
+			return "", err
+		}
+		defer fmt.Fprintln(t.out)
+		defer restore()
+	}
+	line, err := t.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// Prompt for the value of the flag if it is required and not provided. The
+// text is displayed to the user, and defaults to the flag's help.
+func (f *FlagClause) Prompt(text string) *FlagClause {
+	f.prompt = text
+	return f
+}
+
+// Prompt for the value of the argument if it is required and not provided.
+// The text is displayed to the user, and defaults to the argument's help.
+func (a *ArgClause) Prompt(text string) *ArgClause {
+	a.prompt = text
+	return a
+}
+
+// PromptForMissing prompts for the values of all required flags and
+// arguments that are not provided, if stdin is a terminal.
+func (a *Application) PromptForMissing() *Application {
+	a.promptMissing = true
+	return a
+}
+
+// Prompter sets the Prompter used to read missing values. Defaults to
+// prompting on stdin and stderr.
+func (a *Application) Prompter(prompter Prompter) *Application {
+	a.prompter = prompter
+	return a
+}
+
+// Prompt for a value, returning false if prompting is not enabled or possible.
+// The input is set through setter, which may wrap value.
+func (a *Application) promptValue(value, setter Value, prompt, help, name string, secret bool) (bool, error) {
+	if prompt == "" && !a.promptMissing {
+		return false, nil
+	}
+	if a.prompter == nil {
+		a.prompter = NewTerminalPrompter(os.Stdin, os.Stderr)
+	}
+	if !a.prompter.IsTerminal() {
+		return false, nil
+	}
+	if prompt == "" {
+		prompt = strings.TrimSuffix(help, ".")
+	}
+	if prompt == "" {
+		prompt = name
+	}
+	var options []string
+	if value, ok := value.(enumOptionsValue); ok {
+		options = value.getEnumOptions().options
+	}
+	input, err := a.prompter.Prompt(prompt, options, secret)
+	if err != nil {
+		return false, err
+	}
+	if input == "" {
+		return false, nil
+	}
+	if err := setter.Set(input); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,87 @@
+package kingpin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPrompt struct {
+	text    string
+	options []string
+	secret  bool
+}
+
+type testPrompter struct {
+	terminal bool
+	inputs   []string
+	prompts  []testPrompt
+}
+
+func (t *testPrompter) IsTerminal() bool { return t.terminal }
+
+func (t *testPrompter) Prompt(text string, options []string, secret bool) (string, error) {
+	t.prompts = append(t.prompts, testPrompt{text, options, secret})
+	input := t.inputs[0]
+	t.inputs = t.inputs[1:]
+	return input, nil
+}
+
+func TestPromptForFlag(t *testing.T) {
+	prompter := &testPrompter{terminal: true, inputs: []string{"hunter2"}}
+	app := newTestApp().Prompter(prompter)
+	password := app.Flag("password", "").Required().Secret().Prompt("Password").String()
+	format := app.Flag("format", "Output format.").Required().Enum("json", "text")
+	_, err := app.Parse([]string{})
+	assert.EqualError(t, err, "required flag(s) '--format' not provided")
+	assert.Len(t, prompter.prompts, 1)
+
+	prompter.inputs = []string{"hunter2", "text"}
+	prompter.prompts = nil
+	app.PromptForMissing()
+	_, err = app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", *password)
+	assert.Equal(t, "text", *format)
+	assert.Equal(t, []testPrompt{
+		{"Password", nil, true},
+		{"Output format", []string{"json", "text"}, false},
+	}, prompter.prompts)
+	assert.Equal(t, ValueSource{Kind: SourcePrompt}, app.GetFlag("password").Source())
+}
+
+func TestPromptForWrappedEnum(t *testing.T) {
+	prompter := &testPrompter{terminal: true, inputs: []string{"b", "c"}}
+	app := newTestApp().Prompter(prompter)
+	secret := app.Flag("secret", "").Required().Secret().Prompt("Secret").Enum("a", "b")
+	list := app.Flag("list", "").Required().Separator(",").Prompt("List").Enums("c", "d")
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "b", *secret)
+	assert.Equal(t, []string{"c"}, *list)
+	assert.Equal(t, []testPrompt{
+		{"Secret", []string{"a", "b"}, true},
+		{"List", []string{"c", "d"}, false},
+	}, prompter.prompts)
+}
+
+func TestPromptForArg(t *testing.T) {
+	prompter := &testPrompter{terminal: true, inputs: []string{"10", "one"}}
+	app := newTestApp().Prompter(prompter)
+	n := app.Arg("n", "").Required().Prompt("Count").Int()
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, *n)
+
+	_, err = app.Parse([]string{})
+	assert.EqualError(t, err, `invalid value for argument 'n': strconv.ParseInt: parsing "one": invalid syntax`)
+}
+
+func TestPromptNotTerminal(t *testing.T) {
+	prompter := &testPrompter{}
+	app := newTestApp().Prompter(prompter).PromptForMissing()
+	app.Flag("a", "").Required().String()
+	_, err := app.Parse([]string{})
+	assert.EqualError(t, err, "required flag(s) '--a' not provided")
+	assert.Empty(t, prompter.prompts)
+}
@@ -0,0 +1,56 @@
+package kingpin
+
+import "fmt"
+
+// The string displayed in place of secret values.
+const secretMask = "********"
+
+// maskedValue wraps the Value of a secret flag, replacing any error returned
+// by Set(), which may contain the input, and masking the value in String().
+type maskedValue struct {
+	Value
+	name string
+}
+
+func (m maskedValue) Set(s string) error {
+	if err := m.Value.Set(s); err != nil {
+		return fmt.Errorf("invalid value for '--%s'", m.name)
+	}
+	return nil
+}
+
+func (m maskedValue) String() string {
+	if m.Value.String() == "" {
+		return ""
+	}
+	return secretMask
+}
+
+// Get the unmasked value, if the wrapped Value is a Getter.
+func (m maskedValue) Get() interface{} {
+	if g, ok := m.Value.(Getter); ok {
+		return g.Get()
+	}
+	return nil
+}
+
+func (m maskedValue) IsCumulative() bool {
+	r, ok := m.Value.(repeatableFlag)
+	return ok && r.IsCumulative()
+}
+
+// Value without the mask of a secret flag, to check for optional interfaces.
+func unmasked(value Value) Value {
+	if m, ok := value.(maskedValue); ok {
+		return m.Value
+	}
+	return value
+}
+
+func maskSecrets(values []string) []string {
+	out := make([]string, len(values))
+	for i := range values {
+		out[i] = secretMask
+	}
+	return out
+}
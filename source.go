@@ -0,0 +1,61 @@
+package kingpin
+
+import (
+	"fmt"
+)
+
+// SourceKind identifies where the value of a flag or argument came from.
+type SourceKind int
+
+// Value sources.
+const (
+	SourceNone SourceKind = iota
+	SourceDefault
+	SourceEnvar
+	SourceConfig
+	SourceCommandLine
+	SourcePrompt
+)
+
+func (s SourceKind) String() string {
+	switch s {
+	case SourceNone:
+		return "none"
+	case SourceDefault:
+		return "default"
+	case SourceEnvar:
+		return "envar"
+	case SourceConfig:
+		return "config"
+	case SourceCommandLine:
+		return "command line"
+	case SourcePrompt:
+		return "prompt"
+	}
+	return "?"
+}
+
+// ValueSource records where the final value of a flag or argument came from
+// during the most recent parse.
+type ValueSource struct {
+	Kind SourceKind
+	// Envar is the environment variable the value was read from.
+	Envar string
+	// ConfigFile and ConfigKey locate the value in a configuration file.
+	ConfigFile string
+	ConfigKey  string
+	// Index is the Token.Index of the command-line token the value was read from.
+	Index int
+}
+
+func (v ValueSource) String() string {
+	switch v.Kind {
+	case SourceEnvar:
+		return fmt.Sprintf("envar $%s", v.Envar)
+	case SourceConfig:
+		return fmt.Sprintf("config file %s (key %q)", v.ConfigFile, v.ConfigKey)
+	case SourceCommandLine:
+		return fmt.Sprintf("command line (argument %d)", v.Index)
+	}
+	return v.Kind.String()
+}
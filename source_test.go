@@ -0,0 +1,41 @@
+package kingpin
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueSource(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"cmd": {"config": "config"}}`)
+	os.Setenv("TEST_SOURCE_ENVAR", "envar")
+	defer os.Unsetenv("TEST_SOURCE_ENVAR")
+	app := newTestApp().ConfigFile(path)
+	flag := app.Flag("flag", "").String()
+	envar := app.Flag("envar", "").Envar("TEST_SOURCE_ENVAR").String()
+	def := app.Flag("default", "").Default("default").String()
+	unset := app.Flag("unset", "").String()
+	cmd := app.Command("cmd", "")
+	config := cmd.Flag("config", "").String()
+	arg := cmd.Arg("arg", "").String()
+	_, err := app.Parse([]string{"--flag=flag", "cmd", "arg"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"flag", "envar", "default", "", "config", "arg"}, []string{*flag, *envar, *def, *unset, *config, *arg})
+
+	assert.Equal(t, ValueSource{Kind: SourceCommandLine, Index: 1}, app.GetFlag("flag").Source())
+	assert.Equal(t, ValueSource{Kind: SourceEnvar, Envar: "TEST_SOURCE_ENVAR"}, app.GetFlag("envar").Source())
+	assert.Equal(t, ValueSource{Kind: SourceDefault}, app.GetFlag("default").Source())
+	assert.Equal(t, ValueSource{}, app.GetFlag("unset").Source())
+	assert.Equal(t, ValueSource{Kind: SourceConfig, ConfigFile: path, ConfigKey: "cmd.config"}, cmd.GetFlag("config").Source())
+	assert.Equal(t, ValueSource{Kind: SourceCommandLine, Index: 3}, cmd.GetArg("arg").Source())
+	assert.Equal(t, SourceConfig, cmd.Model().Flags[0].Source.Kind)
+}
+
+func TestValueSourceString(t *testing.T) {
+	assert.Equal(t, "none", ValueSource{}.String())
+	assert.Equal(t, "default", ValueSource{Kind: SourceDefault}.String())
+	assert.Equal(t, "envar $FOO", ValueSource{Kind: SourceEnvar, Envar: "FOO"}.String())
+	assert.Equal(t, `config file app.yaml (key "db.host")`, ValueSource{Kind: SourceConfig, ConfigFile: "app.yaml", ConfigKey: "db.host"}.String())
+	assert.Equal(t, "command line (argument 2)", ValueSource{Kind: SourceCommandLine, Index: 2}.String())
+}
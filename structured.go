@@ -0,0 +1,171 @@
+package kingpin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// -- JSON Value
+type jsonValue struct {
+	target interface{}
+}
+
+func newJSONValue(target interface{}) *jsonValue {
+	if t := reflect.TypeOf(target); t == nil || t.Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("expected a pointer but got %T", target))
+	}
+	return &jsonValue{target}
+}
+
+func (j *jsonValue) Set(value string) error {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(j.target); err != nil {
+		return fmt.Errorf("invalid JSON: %s", err)
+	}
+	return nil
+}
+
+func (j *jsonValue) Get() interface{} {
+	return reflect.ValueOf(j.target).Elem().Interface()
+}
+
+func (j *jsonValue) String() string {
+	data, err := json.Marshal(j.target)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func (j *jsonValue) PlaceHolder() string { return "JSON|@FILE" }
+
+func (j *jsonValue) fromFilePrefix() string { return "@" }
+
+// An option of a keyValueOptionsValue.
+type keyValueOption struct {
+	name  string
+	help  string
+	value Value
+}
+
+func (o *keyValueOption) isBool() bool {
+	b, ok := o.value.(boolFlag)
+	return ok && b.IsBoolFlag()
+}
+
+// -- key=value,flag,... Value
+type keyValueOptionsValue struct {
+	target  reflect.Value
+	options []*keyValueOption
+}
+
+func newKeyValueOptionsValue(target interface{}) (*keyValueOptionsValue, error) {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a pointer to a struct but got %T", target)
+	}
+	out := &keyValueOptionsValue{target: v}
+	if err := out.addOptions(v.Elem()); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Add an option for each field of v with an "opt" tag.
+func (k *keyValueOptionsValue) addOptions(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup("opt")
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := k.addOptions(v.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			return fmt.Errorf("field %s: can't bind unexported field", field.Name)
+		}
+		value, err := bindTarget(field, v.Field(i))
+		if err != nil {
+			return err
+		}
+		k.options = append(k.options, &keyValueOption{
+			name:  bindName(name, field.Name),
+			help:  field.Tag.Get("help"),
+			value: value,
+		})
+	}
+	return nil
+}
+
+func (k *keyValueOptionsValue) option(name string) *keyValueOption {
+	for _, option := range k.options {
+		if option.name == name {
+			return option
+		}
+	}
+	return nil
+}
+
+func (k *keyValueOptionsValue) Set(value string) error {
+	for _, item := range splitList(value, ",") {
+		if item == "" {
+			continue
+		}
+		name, v, hasValue := strings.Cut(item, "=")
+		option := k.option(name)
+		if option == nil {
+			return fmt.Errorf("unknown option '%s'", name)
+		}
+		if !hasValue {
+			if !option.isBool() {
+				return fmt.Errorf("expected %s=VALUE got '%s'", name, item)
+			}
+			v = "true"
+		}
+		if err := option.value.Set(v); err != nil {
+			return fmt.Errorf("invalid value for option '%s': %s", name, err)
+		}
+	}
+	return nil
+}
+
+func (k *keyValueOptionsValue) Get() interface{} {
+	return k.target.Elem().Interface()
+}
+
+func (k *keyValueOptionsValue) String() string {
+	out := []string{}
+	for _, option := range k.options {
+		if s := option.value.String(); s != "" {
+			out = append(out, option.name+"="+s)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+func (k *keyValueOptionsValue) IsCumulative() bool { return true }
+
+func (k *keyValueOptionsValue) PlaceHolder() string { return "OPTIONS" }
+
+func (k *keyValueOptionsValue) describeOptions() []*EnumOptionModel {
+	out := make([]*EnumOptionModel, 0, len(k.options))
+	for _, option := range k.options {
+		name := option.name
+		if !option.isBool() {
+			placeholder := strings.ToUpper(option.name)
+			if v, ok := option.value.(placeHolderValue); ok && v.PlaceHolder() != "" {
+				placeholder = v.PlaceHolder()
+			}
+			name += "=" + placeholder
+		}
+		out = append(out, &EnumOptionModel{Name: name, Help: option.help})
+	}
+	return out
+}
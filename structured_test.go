@@ -0,0 +1,135 @@
+package kingpin
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON(t *testing.T) {
+	type limits struct {
+		CPU    float64           `json:"cpu"`
+		Labels map[string]string `json:"labels"`
+	}
+	app := newTestApp()
+	var l limits
+	app.Flag("limits", "").JSON(&l)
+	_, err := app.Parse([]string{`--limits={"cpu": 1.5, "labels": {"a": "b"}}`})
+	assert.NoError(t, err)
+	assert.Equal(t, limits{CPU: 1.5, Labels: map[string]string{"a": "b"}}, l)
+	assert.Equal(t, `{"cpu":1.5,"labels":{"a":"b"}}`, app.GetFlag("limits").value.String())
+
+	path := writeTempFile(t, "limits.json", `{"cpu": 2}`)
+	_, err = app.Parse([]string{"--limits=@" + path})
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, l.CPU)
+
+	_, err = app.Parse([]string{`--limits={"memory": 1}`})
+	assert.EqualError(t, err, `invalid JSON: json: unknown field "memory"`)
+	assert.Equal(t, "--limits=JSON|@FILE", formatFlag(false, app.GetFlag("limits").Model()))
+}
+
+func TestJSONFromFile(t *testing.T) {
+	type limits struct {
+		CPU    float64 `json:"cpu"`
+		Memory int     `json:"memory"`
+	}
+	app := newTestApp()
+	var l, m limits
+	app.Flag("limits", "").Short('l').JSON(&l)
+	app.Flag("more", "").JSON(&m)
+	path := writeTempFile(t, "limits.json", "{\n  \"cpu\": 2,\n  \"memory\": 512\n}\n")
+	_, err := app.Parse([]string{"--limits", "@" + path})
+	assert.NoError(t, err)
+	assert.Equal(t, limits{CPU: 2, Memory: 512}, l)
+
+	l = limits{}
+	_, err = app.Parse([]string{"-l", "@" + path})
+	assert.NoError(t, err)
+	assert.Equal(t, limits{CPU: 2, Memory: 512}, l)
+
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	os.Stdin, _ = os.Open(path)
+	_, err = app.Parse([]string{"--limits=@-", "--more=@-"})
+	assert.EqualError(t, err, "flag 'more': stdin can only be read once")
+}
+
+func TestJSONArgFromFile(t *testing.T) {
+	type limits struct {
+		CPU    float64 `json:"cpu"`
+		Memory int     `json:"memory"`
+	}
+	app := newTestApp()
+	var l limits
+	app.Command("run", "").Arg("limits", "").JSON(&l)
+	path := writeTempFile(t, "limits.json", "{\n  \"cpu\": 2,\n  \"memory\": 512\n}\n")
+	_, err := app.Parse([]string{"run", "@" + path})
+	assert.NoError(t, err)
+	assert.Equal(t, limits{CPU: 2, Memory: 512}, l)
+
+	l = limits{}
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	os.Stdin, _ = os.Open(path)
+	_, err = app.Parse([]string{"run", "@-"})
+	assert.NoError(t, err)
+	assert.Equal(t, limits{CPU: 2, Memory: 512}, l)
+
+	_, err = app.Parse([]string{"run", "@" + path + ".missing"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "argument 'limits': ")
+}
+
+func TestKeyValueOptions(t *testing.T) {
+	type common struct {
+		Timeout time.Duration `opt:"" help:"Mount timeout."`
+	}
+	var opts struct {
+		common
+		ReadOnly bool     `opt:"ro" help:"Mount read-only."`
+		UID      int      `opt:"uid" help:"Owner of all files."`
+		Mode     string   `opt:"mode" enum:"sync,async"`
+		Tags     []string `opt:"tag"`
+		Ignored  string
+	}
+	app := newTestApp()
+	app.Flag("options", "Mount options.").Short('o').KeyValueOptions(&opts)
+	_, err := app.Parse([]string{"-o", "ro,uid=1000", "-o", "timeout=5s,mode=sync,tag=a,tag=b"})
+	assert.NoError(t, err)
+	assert.True(t, opts.ReadOnly)
+	assert.Equal(t, 1000, opts.UID)
+	assert.Equal(t, 5*time.Second, opts.Timeout)
+	assert.Equal(t, "sync", opts.Mode)
+	assert.Equal(t, []string{"a", "b"}, opts.Tags)
+
+	_, err = app.Parse([]string{"-o", "rw"})
+	assert.EqualError(t, err, "unknown option 'rw'")
+	_, err = app.Parse([]string{"-o", "uid"})
+	assert.EqualError(t, err, "expected uid=VALUE got 'uid'")
+	_, err = app.Parse([]string{"-o", "mode=lazy"})
+	assert.EqualError(t, err, "invalid value for option 'mode': enum value must be one of sync,async, got 'lazy'")
+
+	assert.Panics(t, func() { newTestApp().Flag("x", "").KeyValueOptions(opts) })
+}
+
+func TestKeyValueOptionsHelp(t *testing.T) {
+	t.Setenv("COLUMNS", "80")
+	var opts struct {
+		ReadOnly bool `opt:"ro" help:"Mount read-only."`
+		UID      int  `opt:"uid" help:"Owner of all files."`
+	}
+	var buf bytes.Buffer
+	app := New("test", "").Writer(&buf).Terminate(nil)
+	app.Flag("options", "Mount options.").Short('o').KeyValueOptions(&opts)
+	app.Parse([]string{"--help"})
+	assert.Contains(t, buf.String(), `
+  -o, --options=OPTIONS ...  Mount options.
+
+                               ro       Mount read-only.
+                               uid=UID  Owner of all files.
+`)
+}
@@ -152,6 +152,14 @@ var ManPageTemplate = `{{define "FormatFlags" -}}
 .TP
 \fB{{if .Short}}-{{.Short|Char}}, {{end}}--{{.Name}}{{if not .IsBoolFlag}}={{.FormatPlaceHolder}}{{end -}}\fR
 {{.Help}}
+{{range .EnumOptions}}{{if .Help -}}
+.RS
+.TP
+\fB{{.Name}}\fR
+{{.Help}}
+.RE
+{{end -}}
+{{end -}}
 {{end -}}
 {{end -}}
 {{end -}}
@@ -232,6 +240,15 @@ Commands:
 {{end -}}
 `
 
+// Template listing the environment variables consulted by the application.
+var EnvarHelpTemplate = `{{with .App.Envars -}}
+Environment variables:
+{{.|EnvarsToTwoColumns|FormatTwoColumns}}
+{{- else -}}
+No environment variables.
+{{end -}}
+`
+
 var BashCompletionTemplate = `
 _{{.App.Name}}_bash_autocomplete() {
     local cur prev opts base
@@ -248,8 +265,11 @@ complete -F _{{.App.Name}}_bash_autocomplete -o default {{.App.Name}}
 var ZshCompletionTemplate = `#compdef {{.App.Name}}
 
 _{{.App.Name}}() {
-    local matches=($(${words[1]} --completion-bash "${(@)words[2,$CURRENT]}"))
-    compadd -a matches
+    local -a matches
+    matches=(${(f)"$(${words[1]} --completion-descriptions "${(@)words[2,$CURRENT]}")"})
+    # Escape ':' in values, then separate descriptions with ':' for _describe.
+    matches=(${${matches//:/\\:}//$'\t'/:})
+    _describe -t values '{{.App.Name}}' matches
 
     if [[ $compstate[nmatches] -eq 0 && $words[$CURRENT] != -* ]]; then
         _files
@@ -260,3 +280,12 @@ if [[ "$(basename -- ${(%):-%x})" != "_{{.App.Name}}" ]]; then
     compdef _{{.App.Name}} {{.App.Name}}
 fi
 `
+
+var FishCompletionTemplate = `function __complete_{{.App.Name}}
+    set -l args (commandline -opc) (commandline -ct)
+    set -e args[1]
+    {{.App.Name}} --completion-descriptions $args
+end
+
+complete -c {{.App.Name}} -f -a '(__complete_{{.App.Name}})'
+`
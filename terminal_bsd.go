@@ -0,0 +1,12 @@
+//go:build !appengine && (freebsd || darwin || dragonfly || netbsd || openbsd)
+// +build !appengine
+// +build freebsd darwin dragonfly netbsd openbsd
+
+package kingpin
+
+import "syscall"
+
+const (
+	ioctlGetTermios = syscall.TIOCGETA
+	ioctlSetTermios = syscall.TIOCSETA
+)
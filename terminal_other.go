@@ -0,0 +1,14 @@
+//go:build appengine || !(linux || freebsd || darwin || dragonfly || netbsd || openbsd)
+// +build appengine !linux,!freebsd,!darwin,!dragonfly,!netbsd,!openbsd
+
+package kingpin
+
+import "fmt"
+
+func isTerminal(fd uintptr) bool {
+	return false
+}
+
+func disableEcho(fd uintptr) (func(), error) {
+	return nil, fmt.Errorf("disabling echo is not supported on this platform")
+}
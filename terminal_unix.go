@@ -0,0 +1,57 @@
+//go:build !appengine && (linux || freebsd || darwin || dragonfly || netbsd || openbsd)
+// +build !appengine
+// +build linux freebsd darwin dragonfly netbsd openbsd
+
+package kingpin
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func getTermios(fd uintptr) (*syscall.Termios, error) {
+	termios := &syscall.Termios{}
+	if _, _, err := syscall.Syscall6(
+		syscall.SYS_IOCTL,
+		fd,
+		uintptr(ioctlGetTermios),
+		uintptr(unsafe.Pointer(termios)),
+		0, 0, 0,
+	); err != 0 {
+		return nil, err
+	}
+	return termios, nil
+}
+
+func setTermios(fd uintptr, termios *syscall.Termios) error {
+	if _, _, err := syscall.Syscall6(
+		syscall.SYS_IOCTL,
+		fd,
+		uintptr(ioctlSetTermios),
+		uintptr(unsafe.Pointer(termios)),
+		0, 0, 0,
+	); err != 0 {
+		return err
+	}
+	return nil
+}
+
+func isTerminal(fd uintptr) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
+
+// Disable echoing of input, returning a function to restore the previous state.
+func disableEcho(fd uintptr) (func(), error) {
+	termios, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+	previous := *termios
+	termios.Lflag &^= syscall.ECHO
+	termios.Lflag |= syscall.ICANON | syscall.ISIG
+	if err := setTermios(fd, termios); err != nil {
+		return nil, err
+	}
+	return func() { _ = setTermios(fd, &previous) }, nil
+}
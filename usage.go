@@ -35,6 +35,10 @@ func formatTwoColumns(w io.Writer, indent, padding, width int, rows [][2]string)
 		}
 		fmt.Fprintf(w, "%s\n", lines[0])
 		for _, line := range lines[1:] {
+			if line == "" {
+				fmt.Fprintln(w)
+				continue
+			}
 			fmt.Fprintf(w, "%s%s%s\n", indentStr, offsetStr, line)
 		}
 	}
@@ -180,6 +184,26 @@ func (a *Application) UsageForContextWithTemplate(context *ParseContext, indent
 			}
 			return rows
 		},
+		"EnvarsToTwoColumns": func(e []*EnvarModel) [][2]string {
+			rows := [][2]string{}
+			for _, envar := range e {
+				details := []string{envar.Clause}
+				if envar.Command != "" {
+					details[0] += fmt.Sprintf(" of %q", envar.Command)
+				}
+				if len(envar.Default) > 0 {
+					details = append(details, "default: "+strings.Join(envar.Default, ","))
+				}
+				if envar.Set {
+					details = append(details, fmt.Sprintf("set: %q", envar.Value))
+				} else {
+					details = append(details, "not set")
+				}
+				help := strings.TrimSpace(envar.Help + " (" + strings.Join(details, ", ") + ")")
+				rows = append(rows, [2]string{envar.Name, help})
+			}
+			return rows
+		},
 		"FormatTwoColumns": func(rows [][2]string) string {
 			buf := bytes.NewBuffer(nil)
 			formatTwoColumns(buf, indent, indent, width, rows)
@@ -104,3 +104,55 @@ func TestArgEnvVar(t *testing.T) {
 	assert.Contains(t, usage, "($ARG)")
 	assert.Contains(t, usage, "($FLAG)")
 }
+
+func TestEnvarHelp(t *testing.T) {
+	t.Setenv("TEST_ENV_HOST", "db.internal")
+	t.Setenv("TEST_ENV_PASSWORD", "hunter2")
+	t.Setenv("COLUMNS", "80")
+	var buf bytes.Buffer
+	a := New("test", "Test").Writer(&buf).Terminate(nil)
+	a.Flag("debug", "Debug mode.").Envar("TEST_ENV_DEBUG").Bool()
+	a.Flag("hidden", "").Envar("TEST_ENV_HIDDEN").Hidden().Bool()
+	db := a.Command("db", "")
+	db.Flag("host", "Database host.").Envar("TEST_ENV_HOST").Default("localhost").String()
+	db.Flag("password", "Database password.").Envar("TEST_ENV_PASSWORD").EnvarFile().Secret().String()
+	err := a.UsageForContextWithTemplate(&ParseContext{flags: newFlagGroup(), arguments: newArgGroup()}, 2, EnvarHelpTemplate)
+	assert.NoError(t, err)
+	expected := `Environment variables:
+  TEST_ENV_DEBUG          Debug mode. (--debug, not set)
+  TEST_ENV_HOST           Database host. (--host of "db", default: localhost,
+                          set: "db.internal")
+  TEST_ENV_PASSWORD       Database password. (--password of "db", set:
+                          "********")
+  TEST_ENV_PASSWORD_FILE  Database password. (--password of "db", not set)
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestEnumDescribedHelp(t *testing.T) {
+	t.Setenv("COLUMNS", "80")
+	var buf bytes.Buffer
+	a := New("test", "Test").Writer(&buf).Terminate(nil)
+	a.Flag("format", "Output format.").EnumDescribed(map[string]string{
+		"json": "One JSON object per line.",
+		"text": "Human readable text.",
+	})
+	a.Parse([]string{"--help"})
+	assert.Contains(t, buf.String(), `
+  --format=FORMAT  Output format.
+
+                     json  One JSON object per line.
+                     text  Human readable text.
+`)
+
+	buf.Reset()
+	err := a.UsageForContextWithTemplate(&ParseContext{flags: newFlagGroup(), arguments: newArgGroup()}, 2, ManPageTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `Output format.
+.RS
+.TP
+\fBjson\fR
+One JSON object per line.
+.RE
+`)
+}
@@ -3,13 +3,23 @@ package kingpin
 //go:generate go run ./cmd/genvalues/main.go
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding"
 	"fmt"
+	"io"
+	"math/big"
 	"net"
 	"net/url"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +37,9 @@ import (
 // parser makes --name equivalent to -name=true rather than using the next
 // command-line argument, and adds a --no-name counterpart for negating the
 // flag.
+//
+// If a Value has a PlaceHolder() string method, it is used as the flag's
+// placeholder in help when no placeholder or default is set.
 type Value interface {
 	String() string
 	Set(string) error
@@ -58,6 +71,34 @@ type repeatableFlag interface {
 	IsCumulative() bool
 }
 
+// Optional interface for values that resolve relative paths against dir, or
+// the working directory if dir is empty.
+type relativePathValue interface {
+	setBaseDir(dir string)
+}
+
+// Resolve relative paths set on value against dir, returning a function that
+// restores the working directory.
+func withBaseDir(value Value, dir string) func() {
+	r, ok := value.(relativePathValue)
+	if !ok {
+		return func() {}
+	}
+	r.setBaseDir(dir)
+	return func() { r.setBaseDir("") }
+}
+
+// Optional interface for values that are read from a file when given with a
+// prefix, as if AllowFromFile() had been used.
+type fromFileValue interface {
+	fromFilePrefix() string
+}
+
+// Optional interface for values that provide a placeholder for help.
+type placeHolderValue interface {
+	PlaceHolder() string
+}
+
 // Text is the interface to the dynamic value stored in a flag.
 // (The default value is represented as a string.)
 type Text interface {
@@ -82,6 +123,7 @@ type accumulator struct {
 	element func(value interface{}) Value
 	typ     reflect.Type
 	slice   reflect.Value
+	baseDir string // See relativePathValue.
 }
 
 // Use reflection to accumulate values into a slice.
@@ -113,7 +155,11 @@ func (a *accumulator) String() string {
 
 func (a *accumulator) Set(value string) error {
 	e := reflect.New(a.typ)
-	if err := a.element(e.Interface()).Set(value); err != nil {
+	element := a.element(e.Interface())
+	if r, ok := element.(relativePathValue); ok {
+		r.setBaseDir(a.baseDir)
+	}
+	if err := element.Set(value); err != nil {
 		return err
 	}
 	slice := reflect.Append(a.slice.Elem(), e.Elem())
@@ -129,6 +175,90 @@ func (a *accumulator) IsCumulative() bool {
 	return true
 }
 
+func (a *accumulator) setBaseDir(dir string) { a.baseDir = dir }
+
+func (a *accumulator) PlaceHolder() string {
+	if v, ok := a.element(reflect.New(a.typ).Interface()).(placeHolderValue); ok {
+		return v.PlaceHolder()
+	}
+	return ""
+}
+
+// Accumulate returns a Value that appends each value it is set to to target,
+// which must be a pointer to a slice of a type supported by Bind, including
+// types whose pointer implements Value or encoding.TextUnmarshaler.
+//
+//	var rules []Rule
+//	app.Flag("rule", "Add a rule.").SetValue(kingpin.Accumulate(&rules))
+func Accumulate(target interface{}) Value {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Slice ||
+		valueForPointer(reflect.New(t.Elem().Elem()).Interface()) == nil {
+		panic(fmt.Sprintf("can't accumulate values into %T", target))
+	}
+	return newAccumulator(target, valueForPointer)
+}
+
+// Collects a new Value for each value it is set to.
+type repeatedValue struct {
+	target  *[]Value
+	factory func() Value
+}
+
+func (r *repeatedValue) Set(value string) error {
+	element := r.factory()
+	if err := element.Set(value); err != nil {
+		return err
+	}
+	*r.target = append(*r.target, element)
+	return nil
+}
+
+// Get returns the value of each element, or the element if it is not a Getter.
+func (r *repeatedValue) Get() interface{} {
+	out := make([]interface{}, 0, len(*r.target))
+	for _, element := range *r.target {
+		if g, ok := element.(Getter); ok {
+			out = append(out, g.Get())
+		} else {
+			out = append(out, element)
+		}
+	}
+	return out
+}
+
+func (r *repeatedValue) String() string {
+	out := make([]string, 0, len(*r.target))
+	for _, element := range *r.target {
+		out = append(out, element.String())
+	}
+	return strings.Join(out, ",")
+}
+
+func (r *repeatedValue) IsCumulative() bool { return true }
+
+func (r *repeatedValue) PlaceHolder() string {
+	if v, ok := r.factory().(placeHolderValue); ok {
+		return v.PlaceHolder()
+	}
+	return ""
+}
+
+// Sets each element of a separated list of values.
+type separatedValue struct {
+	Value
+	separator string
+}
+
+func (s *separatedValue) Set(value string) error {
+	for _, element := range splitList(value, s.separator) {
+		if err := s.Value.Set(element); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (b *boolValue) IsBoolFlag() bool { return true }
 
 // -- time.Duration Value
@@ -148,36 +278,151 @@ func (d *durationValue) Get() interface{} { return time.Duration(*d) }
 
 func (d *durationValue) String() string { return (*time.Duration)(d).String() }
 
-// -- map[string]string Value
-type stringMapValue map[string]string
+// -- time.Time Value
 
-func newStringMapValue(p *map[string]string) *stringMapValue {
-	return (*stringMapValue)(p)
+// Layouts accepted by Time() when none are given.
+var defaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	dateLayout,
 }
 
-var stringMapRegex = regexp.MustCompile("[:=]")
+const dateLayout = "2006-01-02"
 
-func (s *stringMapValue) Set(value string) error {
-	parts := stringMapRegex.Split(value, 2)
-	if len(parts) != 2 {
-		return fmt.Errorf("expected KEY=VALUE got '%s'", value)
+// Returns the current time, replaced in tests.
+var timeNow = time.Now
+
+var relativeTimeRegexp = regexp.MustCompile(`^(now|today|yesterday|tomorrow)?\s*(?:([+-])\s*(\S+))?$`)
+
+type timeValue struct {
+	v       *time.Time
+	layouts []string
+	date    bool // Truncate to midnight.
+}
+
+func newTimeValue(p *time.Time, layouts ...string) *timeValue {
+	if len(layouts) == 0 {
+		layouts = defaultTimeLayouts
+	}
+	return &timeValue{v: p, layouts: layouts}
+}
+
+func newDateValue(p *time.Time) *timeValue {
+	return &timeValue{v: p, layouts: []string{dateLayout}, date: true}
+}
+
+func (t *timeValue) Set(s string) error {
+	v, err := parseTime(s, t.layouts)
+	if err != nil {
+		return err
 	}
-	(*s)[parts[0]] = parts[1]
+	if t.date {
+		v = midnight(v)
+	}
+	*t.v = v
 	return nil
 }
 
-func (s *stringMapValue) Get() interface{} {
-	return (map[string]string)(*s)
+func (t *timeValue) Get() interface{} { return *t.v }
+
+func (t *timeValue) String() string {
+	if t.v.IsZero() {
+		return ""
+	}
+	return t.v.Format(t.layouts[0])
 }
 
-func (s *stringMapValue) String() string {
-	return fmt.Sprintf("%s", map[string]string(*s))
+func (t *timeValue) PlaceHolder() string {
+	if t.date {
+		return "YYYY-MM-DD"
+	}
+	return "TIME"
 }
 
-func (s *stringMapValue) IsCumulative() bool {
-	return true
+// Parse a time in one of layouts, in local time unless the layout includes a
+// zone, or relative to the current time, eg. "now-1h", "yesterday" or
+// "tomorrow+2h".
+func parseTime(s string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	groups := relativeTimeRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if groups == nil || (groups[1] == "" && groups[2] == "") {
+		return time.Time{}, fmt.Errorf("invalid time '%s', expected %s or a relative time such as now-1h", s, layouts[0])
+	}
+	t := timeNow()
+	switch groups[1] {
+	case "today":
+		t = midnight(t)
+	case "yesterday":
+		t = midnight(t).AddDate(0, 0, -1)
+	case "tomorrow":
+		t = midnight(t).AddDate(0, 0, 1)
+	}
+	if groups[2] != "" {
+		offset, err := str2duration.ParseDuration(groups[3])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid relative time '%s': %s", s, err)
+		}
+		if groups[2] == "-" {
+			offset = -offset
+		}
+		t = t.Add(offset)
+	}
+	return t, nil
 }
 
+func midnight(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// Parse seconds since the Unix epoch, with an optional fraction.
+func parseUnixTime(s string) (time.Time, error) {
+	secs, frac, _ := strings.Cut(s, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil || len(frac) > 9 {
+		return time.Time{}, fmt.Errorf("invalid Unix time '%s'", s)
+	}
+	var nsec int64
+	if frac != "" {
+		n, err := strconv.ParseUint((frac + "00000000")[:9], 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid Unix time '%s'", s)
+		}
+		nsec = int64(n)
+		if strings.HasPrefix(secs, "-") {
+			nsec = -nsec
+		}
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+func formatUnixTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// -- map[string]string Value
+var stringMapRegex = regexp.MustCompile("[:=]")
+
+func newStringMapValue(p *map[string]string, options ...MapOption) Value {
+	value := &genericMapValue[string, string]{target: p, parseKey: parseString, parseValue: parseString}
+	for _, option := range options {
+		option(&value.mapOptions)
+	}
+	return value
+}
+
+func parseString(s string) (string, error) { return s, nil }
+
 // -- net.IP Value
 type ipValue net.IP
 
@@ -228,17 +473,134 @@ func (i *tcpAddrValue) String() string {
 	return (*i.addr).String()
 }
 
+// Parse an IP network in CIDR notation.
+func parseIPNet(s string) (*net.IPNet, error) {
+	_, ipNet, err := net.ParseCIDR(s)
+	return ipNet, err
+}
+
+// Format a value such as a netip.Addr, which is empty if it is not valid.
+func formatValid(v interface {
+	IsValid() bool
+	String() string
+}) string {
+	if !v.IsValid() {
+		return ""
+	}
+	return v.String()
+}
+
+// Parse a port number or service name.
+func parsePort(s string) (uint16, error) {
+	if s != "" && strings.Trim(s, "0123456789") == "" {
+		port, err := strconv.ParseUint(s, 10, 16)
+		if err != nil || port == 0 {
+			return 0, fmt.Errorf("invalid port '%s': must be 1-65535", s)
+		}
+		return uint16(port), nil
+	}
+	if !isServiceName(s) {
+		return 0, fmt.Errorf("invalid port '%s'", s)
+	}
+	n, err := net.LookupPort("tcp", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port '%s': %s", s, err)
+	}
+	return uint16(n), nil
+}
+
+// Whether s may be a service name, eg. "http", which contains a letter.
+func isServiceName(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-')
+	}) < 0 && strings.IndexFunc(s, func(r rune) bool {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+	}) >= 0
+}
+
+// PortRange is an inclusive range of ports.
+type PortRange struct {
+	From, To uint16
+}
+
+func (p PortRange) String() string {
+	switch {
+	case p.From == 0 && p.To == 0:
+		return ""
+	case p.From == p.To:
+		return strconv.Itoa(int(p.From))
+	}
+	return fmt.Sprintf("%d-%d", p.From, p.To)
+}
+
+// Parse a port or range of ports, eg. 8000-8080.
+func parsePortRange(s string) (PortRange, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		to = from
+	}
+	fromPort, err := parsePort(from)
+	if err != nil {
+		return PortRange{}, err
+	}
+	toPort, err := parsePort(to)
+	if err != nil {
+		return PortRange{}, err
+	}
+	if toPort < fromPort {
+		return PortRange{}, fmt.Errorf("invalid port range '%s', %d is less than %d", s, toPort, fromPort)
+	}
+	return PortRange{fromPort, toPort}, nil
+}
+
+// -- host:port Value
+type hostPortValue struct {
+	v           *string
+	defaultPort uint16
+}
+
+func newHostPortValue(p *string, defaultPort uint16) *hostPortValue {
+	return &hostPortValue{p, defaultPort}
+}
+
+func (h *hostPortValue) Set(value string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		// Missing port, possibly an unbracketed IPv6 address.
+		host, port = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"), strconv.Itoa(int(h.defaultPort))
+		if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+			return fmt.Errorf("invalid address '%s': %s", value, err)
+		}
+	}
+	if host == "" {
+		return fmt.Errorf("invalid address '%s': missing host", value)
+	}
+	if _, err := parsePort(port); err != nil {
+		return fmt.Errorf("invalid address '%s': %s", value, err)
+	}
+	*h.v = net.JoinHostPort(host, port)
+	return nil
+}
+
+func (h *hostPortValue) Get() interface{} { return *h.v }
+
+func (h *hostPortValue) String() string { return *h.v }
+
+func (h *hostPortValue) PlaceHolder() string { return "HOST[:PORT]" }
+
 // -- existingFile Value
 
 type fileStatValue struct {
 	path      *string
 	predicate func(os.FileInfo) error
+	checks    []func(path string) error // Additional checks after predicate.
 }
 
-func newFileStatValue(p *string, predicate func(os.FileInfo) error) *fileStatValue {
+func newFileStatValue(p *string, predicate func(os.FileInfo) error, checks ...func(path string) error) *fileStatValue {
 	return &fileStatValue{
 		path:      p,
 		predicate: predicate,
+		checks:    checks,
 	}
 }
 
@@ -250,6 +612,11 @@ func (e *fileStatValue) Set(value string) error {
 	} else if err := e.predicate(s); err != nil {
 		return err
 	}
+	for _, check := range e.checks {
+		if err := check(value); err != nil {
+			return err
+		}
+	}
 	*e.path = value
 	return nil
 }
@@ -262,6 +629,196 @@ func (e *fileStatValue) String() string {
 	return *e.path
 }
 
+// -- path Value
+type pathValue struct {
+	path    *string
+	baseDir string
+}
+
+func newPathValue(p *string) *pathValue {
+	return &pathValue{path: p}
+}
+
+func (p *pathValue) Set(value string) error {
+	path, err := resolvePath(value, p.baseDir)
+	if err != nil {
+		return err
+	}
+	*p.path = path
+	return nil
+}
+
+func (p *pathValue) Get() interface{} { return *p.path }
+
+func (p *pathValue) String() string { return *p.path }
+
+func (p *pathValue) setBaseDir(dir string) { p.baseDir = dir }
+
+// Expand a leading ~ to the user's home directory.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("can't expand '%s': %s", path, err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// Expand ~ and make path absolute, relative to baseDir if it is not empty.
+func resolvePath(path, baseDir string) (string, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+	if baseDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return filepath.Abs(path)
+}
+
+// Check that dir is a directory that files can be created in.
+func checkWritableDir(dir string) error {
+	s, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("directory '%s' does not exist", dir)
+	} else if err != nil {
+		return err
+	} else if !s.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", dir)
+	}
+	if !isWritableDir(dir) {
+		return fmt.Errorf("directory '%s' is not writable", dir)
+	}
+	return nil
+}
+
+// -- new file Value
+type absentFileValue struct {
+	path *string
+}
+
+func newNewFileValue(p *string) *absentFileValue {
+	return &absentFileValue{p}
+}
+
+func (n *absentFileValue) Set(value string) error {
+	path, err := expandHome(value)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("path '%s' already exists", value)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := checkWritableDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	*n.path = path
+	return nil
+}
+
+func (n *absentFileValue) Get() interface{} { return *n.path }
+
+func (n *absentFileValue) String() string { return *n.path }
+
+func newWritableDirValue(p *string) *fileStatValue {
+	return newFileStatValue(p, func(os.FileInfo) error { return nil }, checkWritableDir)
+}
+
+// -- executable path Value
+type executablePathValue struct {
+	path *string
+}
+
+func newExecutablePathValue(p *string) *executablePathValue {
+	return &executablePathValue{p}
+}
+
+// Set to a path, or the name of an executable in $PATH.
+func (e *executablePathValue) Set(value string) error {
+	path, err := expandHome(value)
+	if err != nil {
+		return err
+	}
+	if !strings.ContainsRune(path, filepath.Separator) && !strings.ContainsRune(path, '/') {
+		found, err := exec.LookPath(path)
+		if err != nil {
+			return fmt.Errorf("executable '%s' not found in $PATH", value)
+		}
+		*e.path = found
+		return nil
+	}
+	s, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("path '%s' does not exist", value)
+	} else if err != nil {
+		return err
+	} else if s.IsDir() {
+		return fmt.Errorf("'%s' is a directory", value)
+	} else if runtime.GOOS != "windows" && s.Mode()&0111 == 0 {
+		return fmt.Errorf("'%s' is not executable", value)
+	}
+	*e.path = path
+	return nil
+}
+
+func (e *executablePathValue) Get() interface{} { return *e.path }
+
+func (e *executablePathValue) String() string { return *e.path }
+
+// -- glob Value
+type globValue struct {
+	paths *[]string
+}
+
+func newGlobValue(p *[]string) *globValue {
+	return &globValue{p}
+}
+
+// Set appends the files matching a glob pattern.
+func (g *globValue) Set(value string) error {
+	pattern, err := expandHome(value)
+	if err != nil {
+		return err
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern '%s': %s", value, err)
+	}
+	files := []string{}
+	for _, match := range matches {
+		if s, err := os.Stat(match); err == nil && !s.IsDir() {
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 {
+		if len(matches) == 0 && pattern == globEscape(pattern) {
+			return fmt.Errorf("path '%s' does not exist", value)
+		} else if len(matches) == 1 && pattern == globEscape(pattern) {
+			return fmt.Errorf("'%s' is a directory", value)
+		}
+		return fmt.Errorf("pattern '%s' matched no files", value)
+	}
+	*g.paths = append(*g.paths, files...)
+	return nil
+}
+
+func (g *globValue) Get() interface{} { return *g.paths }
+
+func (g *globValue) String() string { return strings.Join(*g.paths, ",") }
+
+func (g *globValue) IsCumulative() bool { return true }
+
+func (g *globValue) PlaceHolder() string { return "PATTERN" }
+
+// Escape glob metacharacters in pattern.
+func globEscape(pattern string) string {
+	return strings.NewReplacer("*", "\\*", "?", "\\?", "[", "\\[").Replace(pattern)
+}
+
 // -- os.File value
 
 type fileValue struct {
@@ -275,6 +832,14 @@ func newFileValue(p **os.File, flag int, perm os.FileMode) *fileValue {
 }
 
 func (f *fileValue) Set(value string) error {
+	if value == "-" {
+		if f.flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+			*f.f = os.Stdout
+		} else {
+			*f.f = os.Stdin
+		}
+		return nil
+	}
 	if fd, err := os.OpenFile(value, f.flag, f.perm); err != nil {
 		return err
 	} else {
@@ -294,6 +859,107 @@ func (f *fileValue) String() string {
 	return (*f.f).Name()
 }
 
+func (f *fileValue) PlaceHolder() string { return "FILE|-" }
+
+// -- io.ReadCloser Value
+type inputValue struct {
+	r          *io.ReadCloser
+	name       string
+	decompress bool // Transparently decompress gzip input.
+}
+
+func newInputValue(p *io.ReadCloser, decompress bool) *inputValue {
+	return &inputValue{r: p, decompress: decompress}
+}
+
+func (i *inputValue) Set(value string) error {
+	var r io.ReadCloser = io.NopCloser(os.Stdin)
+	if value != "-" {
+		f, err := os.Open(value)
+		if err != nil {
+			return err
+		}
+		r = f
+	}
+	if i.decompress {
+		var err error
+		if r, err = gunzipReader(r); err != nil {
+			r.Close()
+			return fmt.Errorf("%s: %s", value, err)
+		}
+	}
+	*i.r = r
+	i.name = value
+	return nil
+}
+
+func (i *inputValue) Get() interface{} { return *i.r }
+
+func (i *inputValue) String() string { return i.name }
+
+func (i *inputValue) PlaceHolder() string { return "FILE|-" }
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// Decompress r if it starts with the gzip magic number.
+func gunzipReader(r io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(2)
+	if !bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		return readCloser{br, r}, nil
+	}
+	gz, err := gzip.NewReader(br)
+	if err != nil {
+		return r, err
+	}
+	return readCloser{gz, r}, nil
+}
+
+// -- io.WriteCloser Value
+type outputValue struct {
+	w      *io.WriteCloser
+	name   string
+	opened *os.File // The file created by the last Set(), if any.
+}
+
+func newOutputValue(p *io.WriteCloser) *outputValue {
+	return &outputValue{w: p}
+}
+
+// Set the writer, closing any file created by a previous Set().
+func (o *outputValue) Set(value string) error {
+	var f *os.File
+	if value == "-" {
+		*o.w = nopWriteCloser{os.Stdout}
+	} else {
+		var err error
+		if f, err = os.Create(value); err != nil {
+			return err
+		}
+		*o.w = f
+	}
+	if o.opened != nil {
+		o.opened.Close()
+	}
+	o.name, o.opened = value, f
+	return nil
+}
+
+func (o *outputValue) Get() interface{} { return *o.w }
+
+func (o *outputValue) String() string { return o.name }
+
+func (o *outputValue) PlaceHolder() string { return "FILE|-" }
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
 // -- url.URL Value
 type urlValue struct {
 	u **url.URL
@@ -355,16 +1021,66 @@ func (u *urlListValue) IsCumulative() bool {
 	return true
 }
 
+// The options of an enum value.
+type enumOptions struct {
+	options      []string
+	descriptions map[string]string // Help for each option, if any.
+	ignoreCase   bool
+}
+
+// Create options from a map of option names to descriptions, sorted by name.
+func newDescribedEnumOptions(descriptions map[string]string) enumOptions {
+	options := make([]string, 0, len(descriptions))
+	for option := range descriptions {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	return enumOptions{options: options, descriptions: descriptions}
+}
+
+// Return the option matching value.
+func (e *enumOptions) match(value string) (string, error) {
+	for _, option := range e.options {
+		if option == value || (e.ignoreCase && strings.EqualFold(option, value)) {
+			return option, nil
+		}
+	}
+	return "", fmt.Errorf("enum value must be one of %s, got '%s'", strings.Join(e.options, ","), value)
+}
+
+// Return the description of option, if any.
+func (e *enumOptions) describe(option string) string {
+	return e.descriptions[option]
+}
+
+func (e *enumOptions) setIgnoreCase() { e.ignoreCase = true }
+
+func (e *enumOptions) describeOptions() []*EnumOptionModel {
+	out := make([]*EnumOptionModel, 0, len(e.options))
+	for _, option := range e.options {
+		out = append(out, &EnumOptionModel{Name: option, Help: e.describe(option)})
+	}
+	return out
+}
+
+func (e *enumOptions) getEnumOptions() *enumOptions { return e }
+
+// Implemented by enum values.
+type enumOptionsValue interface {
+	getEnumOptions() *enumOptions
+	setIgnoreCase()
+}
+
 // A flag whose value must be in a set of options.
 type enumValue struct {
-	value   *string
-	options []string
+	enumOptions
+	value *string
 }
 
 func newEnumFlag(target *string, options ...string) *enumValue {
 	return &enumValue{
-		value:   target,
-		options: options,
+		value:       target,
+		enumOptions: enumOptions{options: options},
 	}
 }
 
@@ -373,13 +1089,12 @@ func (a *enumValue) String() string {
 }
 
 func (a *enumValue) Set(value string) error {
-	for _, v := range a.options {
-		if v == value {
-			*a.value = value
-			return nil
-		}
+	option, err := a.match(value)
+	if err != nil {
+		return err
 	}
-	return fmt.Errorf("enum value must be one of %s, got '%s'", strings.Join(a.options, ","), value)
+	*a.value = option
+	return nil
 }
 
 func (e *enumValue) Get() interface{} {
@@ -388,25 +1103,24 @@ func (e *enumValue) Get() interface{} {
 
 // -- []string Enum Value
 type enumsValue struct {
-	value   *[]string
-	options []string
+	enumOptions
+	value *[]string
 }
 
 func newEnumsFlag(target *[]string, options ...string) *enumsValue {
 	return &enumsValue{
-		value:   target,
-		options: options,
+		value:       target,
+		enumOptions: enumOptions{options: options},
 	}
 }
 
 func (s *enumsValue) Set(value string) error {
-	for _, v := range s.options {
-		if v == value {
-			*s.value = append(*s.value, value)
-			return nil
-		}
+	option, err := s.match(value)
+	if err != nil {
+		return err
 	}
-	return fmt.Errorf("enum value must be one of %s, got '%s'", strings.Join(s.options, ","), value)
+	*s.value = append(*s.value, option)
+	return nil
 }
 
 func (e *enumsValue) Get() interface{} {
@@ -438,6 +1152,96 @@ func (d *bytesValue) Get() interface{} { return units.Base2Bytes(*d) }
 
 func (d *bytesValue) String() string { return (*units.Base2Bytes)(d).String() }
 
+// -- units.MetricBytes Value
+type metricBytesValue units.MetricBytes
+
+func newMetricBytesValue(p *units.MetricBytes) *metricBytesValue {
+	return (*metricBytesValue)(p)
+}
+
+func (d *metricBytesValue) Set(s string) error {
+	v, err := units.ParseMetricBytes(s)
+	*d = metricBytesValue(v)
+	return err
+}
+
+func (d *metricBytesValue) Get() interface{} { return units.MetricBytes(*d) }
+
+func (d *metricBytesValue) String() string { return (*units.MetricBytes)(d).String() }
+
+// Parse a decimal integer, or one with an explicit 0x, 0b or 0o prefix. Digits
+// may be separated by _, and leading zeros do not make the number octal.
+func parseInt(s string, bitSize int) (int64, error) {
+	v, err := strconv.ParseInt(decimalLiteral(s), 0, bitSize)
+	if ne, ok := err.(*strconv.NumError); ok {
+		ne.Num = s
+	}
+	return v, err
+}
+
+// Like parseInt but for unsigned integers.
+func parseUint(s string, bitSize int) (uint64, error) {
+	v, err := strconv.ParseUint(decimalLiteral(s), 0, bitSize)
+	if ne, ok := err.(*strconv.NumError); ok {
+		ne.Num = s
+	}
+	return v, err
+}
+
+// Strip leading zeros from an integer without a base prefix, so it is parsed
+// as decimal by strconv with base 0.
+func decimalLiteral(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	if len(s) < 2 || s[0] != '0' || strings.IndexByte("xXbBoO", s[1]) != -1 {
+		return sign + s
+	}
+	if s = strings.TrimLeft(s, "0"); s == "" {
+		s = "0"
+	}
+	return sign + s
+}
+
+var quantitySuffixes = map[string]int64{
+	"k": 1e3, "K": 1e3, "M": 1e6, "G": 1e9, "T": 1e12, "P": 1e15, "E": 1e18,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+var quantityRegexp = regexp.MustCompile(`^([-+]?[0-9][0-9_]*(?:\.[0-9_]*)?)([kKMGTPE]i?)?$`)
+
+// Parse an integer with an optional SI (k, M, G, ...) or binary (Ki, Mi, Gi,
+// ...) suffix, eg. 1.5G.
+func parseQuantity(s string) (int64, error) {
+	groups := quantityRegexp.FindStringSubmatch(s)
+	if groups == nil {
+		return 0, fmt.Errorf("invalid quantity '%s'", s)
+	}
+	number := strings.ReplaceAll(groups[1], "_", "")
+	if groups[2] == "" && !strings.Contains(number, ".") {
+		return strconv.ParseInt(number, 10, 64)
+	}
+	r, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, fmt.Errorf("invalid quantity '%s'", s)
+	}
+	if groups[2] != "" {
+		multiplier, ok := quantitySuffixes[groups[2]]
+		if !ok {
+			return 0, fmt.Errorf("invalid quantity '%s', unknown suffix %q", s, groups[2])
+		}
+		r.Mul(r, new(big.Rat).SetInt64(multiplier))
+	}
+	if !r.IsInt() {
+		return 0, fmt.Errorf("invalid quantity '%s', not a whole number", s)
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("invalid quantity '%s', value out of range", s)
+	}
+	return r.Num().Int64(), nil
+}
+
 func newExistingFileValue(target *string) *fileStatValue {
 	return newFileStatValue(target, func(s os.FileInfo) error {
 		if s.IsDir() {
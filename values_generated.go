@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net"
+	"net/netip"
 	"regexp"
 	"strconv"
 	"time"
@@ -105,7 +106,7 @@ func newUintValue(p *uint) *uintValue {
 }
 
 func (f *uintValue) Set(s string) error {
-	v, err := strconv.ParseUint(s, 0, 64)
+	v, err := parseUint(s, 64)
 	if err == nil {
 		*f.v = (uint)(v)
 	}
@@ -148,7 +149,7 @@ func newUint8Value(p *uint8) *uint8Value {
 }
 
 func (f *uint8Value) Set(s string) error {
-	v, err := strconv.ParseUint(s, 0, 8)
+	v, err := parseUint(s, 8)
 	if err == nil {
 		*f.v = (uint8)(v)
 	}
@@ -191,7 +192,7 @@ func newUint16Value(p *uint16) *uint16Value {
 }
 
 func (f *uint16Value) Set(s string) error {
-	v, err := strconv.ParseUint(s, 0, 16)
+	v, err := parseUint(s, 16)
 	if err == nil {
 		*f.v = (uint16)(v)
 	}
@@ -234,7 +235,7 @@ func newUint32Value(p *uint32) *uint32Value {
 }
 
 func (f *uint32Value) Set(s string) error {
-	v, err := strconv.ParseUint(s, 0, 32)
+	v, err := parseUint(s, 32)
 	if err == nil {
 		*f.v = (uint32)(v)
 	}
@@ -277,7 +278,7 @@ func newUint64Value(p *uint64) *uint64Value {
 }
 
 func (f *uint64Value) Set(s string) error {
-	v, err := strconv.ParseUint(s, 0, 64)
+	v, err := parseUint(s, 64)
 	if err == nil {
 		*f.v = (uint64)(v)
 	}
@@ -320,7 +321,7 @@ func newIntValue(p *int) *intValue {
 }
 
 func (f *intValue) Set(s string) error {
-	v, err := strconv.ParseFloat(s, 64)
+	v, err := parseInt(s, strconv.IntSize)
 	if err == nil {
 		*f.v = (int)(v)
 	}
@@ -363,7 +364,7 @@ func newInt8Value(p *int8) *int8Value {
 }
 
 func (f *int8Value) Set(s string) error {
-	v, err := strconv.ParseInt(s, 0, 8)
+	v, err := parseInt(s, 8)
 	if err == nil {
 		*f.v = (int8)(v)
 	}
@@ -406,7 +407,7 @@ func newInt16Value(p *int16) *int16Value {
 }
 
 func (f *int16Value) Set(s string) error {
-	v, err := strconv.ParseInt(s, 0, 16)
+	v, err := parseInt(s, 16)
 	if err == nil {
 		*f.v = (int16)(v)
 	}
@@ -449,7 +450,7 @@ func newInt32Value(p *int32) *int32Value {
 }
 
 func (f *int32Value) Set(s string) error {
-	v, err := strconv.ParseInt(s, 0, 32)
+	v, err := parseInt(s, 32)
 	if err == nil {
 		*f.v = (int32)(v)
 	}
@@ -492,7 +493,7 @@ func newInt64Value(p *int64) *int64Value {
 }
 
 func (f *int64Value) Set(s string) error {
-	v, err := strconv.ParseInt(s, 0, 64)
+	v, err := parseInt(s, 64)
 	if err == nil {
 		*f.v = (int64)(v)
 	}
@@ -570,6 +571,49 @@ func (p *parserMixin) Float64ListVar(target *[]float64) {
 	}))
 }
 
+// -- int64 Value
+type quantityValue struct{ v *int64 }
+
+func newQuantityValue(p *int64) *quantityValue {
+	return &quantityValue{p}
+}
+
+func (f *quantityValue) Set(s string) error {
+	v, err := parseQuantity(s)
+	if err == nil {
+		*f.v = (int64)(v)
+	}
+	return err
+}
+
+func (f *quantityValue) Get() interface{} { return (int64)(*f.v) }
+
+func (f *quantityValue) String() string { return fmt.Sprintf("%v", *f.v) }
+
+// Quantity parses an integer with an optional SI (k, M, G, ...) or binary (Ki, Mi, Gi, ...) suffix, eg. 1.5G.
+func (p *parserMixin) Quantity() (target *int64) {
+	target = new(int64)
+	p.QuantityVar(target)
+	return
+}
+
+func (p *parserMixin) QuantityVar(target *int64) {
+	p.SetValue(newQuantityValue(target))
+}
+
+// Quantities accumulates int64 values into a slice.
+func (p *parserMixin) Quantities() (target *[]int64) {
+	target = new([]int64)
+	p.QuantitiesVar(target)
+	return
+}
+
+func (p *parserMixin) QuantitiesVar(target *[]int64) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newQuantityValue(v.(*int64))
+	}))
+}
+
 // -- float32 Value
 type float32Value struct{ v *float32 }
 
@@ -691,6 +735,58 @@ func (p *parserMixin) ExistingFilesOrDirsVar(target *[]string) {
 	}))
 }
 
+// NewFiles accumulates string values into a slice.
+func (p *parserMixin) NewFiles() (target *[]string) {
+	target = new([]string)
+	p.NewFilesVar(target)
+	return
+}
+
+func (p *parserMixin) NewFilesVar(target *[]string) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newNewFileValue(v.(*string))
+	}))
+}
+
+// WritableDirs accumulates string values into a slice.
+func (p *parserMixin) WritableDirs() (target *[]string) {
+	target = new([]string)
+	p.WritableDirsVar(target)
+	return
+}
+
+func (p *parserMixin) WritableDirsVar(target *[]string) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newWritableDirValue(v.(*string))
+	}))
+}
+
+// ExecutablePaths accumulates string values into a slice.
+func (p *parserMixin) ExecutablePaths() (target *[]string) {
+	target = new([]string)
+	p.ExecutablePathsVar(target)
+	return
+}
+
+func (p *parserMixin) ExecutablePathsVar(target *[]string) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newExecutablePathValue(v.(*string))
+	}))
+}
+
+// Paths accumulates string values into a slice.
+func (p *parserMixin) Paths() (target *[]string) {
+	target = new([]string)
+	p.PathsVar(target)
+	return
+}
+
+func (p *parserMixin) PathsVar(target *[]string) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newPathValue(v.(*string))
+	}))
+}
+
 // -- *regexp.Regexp Value
 type regexpValue struct{ v **regexp.Regexp }
 
@@ -819,3 +915,488 @@ func (p *parserMixin) HexBytesListVar(target *[][]byte) {
 		return newHexBytesValue(v.(*[]byte))
 	}))
 }
+
+// Times accumulates time.Time values into a slice.
+func (p *parserMixin) Times(layouts ...string) (target *[]time.Time) {
+	target = new([]time.Time)
+	p.TimesVar(target, layouts...)
+	return
+}
+
+func (p *parserMixin) TimesVar(target *[]time.Time, layouts ...string) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newTimeValue(v.(*time.Time), layouts...)
+	}))
+}
+
+// Dates accumulates time.Time values into a slice.
+func (p *parserMixin) Dates() (target *[]time.Time) {
+	target = new([]time.Time)
+	p.DatesVar(target)
+	return
+}
+
+func (p *parserMixin) DatesVar(target *[]time.Time) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newDateValue(v.(*time.Time))
+	}))
+}
+
+// -- time.Time Value
+type unixTimeValue struct{ v *time.Time }
+
+func newUnixTimeValue(p *time.Time) *unixTimeValue {
+	return &unixTimeValue{p}
+}
+
+func (f *unixTimeValue) Set(s string) error {
+	v, err := parseUnixTime(s)
+	if err == nil {
+		*f.v = (time.Time)(v)
+	}
+	return err
+}
+
+func (f *unixTimeValue) Get() interface{} { return (time.Time)(*f.v) }
+
+func (f *unixTimeValue) String() string { return formatUnixTime(*f.v) }
+
+// UnixTime parses seconds since the Unix epoch, with an optional fraction.
+func (p *parserMixin) UnixTime() (target *time.Time) {
+	target = new(time.Time)
+	p.UnixTimeVar(target)
+	return
+}
+
+func (p *parserMixin) UnixTimeVar(target *time.Time) {
+	p.SetValue(newUnixTimeValue(target))
+}
+
+// UnixTimes accumulates time.Time values into a slice.
+func (p *parserMixin) UnixTimes() (target *[]time.Time) {
+	target = new([]time.Time)
+	p.UnixTimesVar(target)
+	return
+}
+
+func (p *parserMixin) UnixTimesVar(target *[]time.Time) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newUnixTimeValue(v.(*time.Time))
+	}))
+}
+
+// -- *time.Location Value
+type timeZoneValue struct{ v **time.Location }
+
+func newTimeZoneValue(p **time.Location) *timeZoneValue {
+	return &timeZoneValue{p}
+}
+
+func (f *timeZoneValue) Set(s string) error {
+	v, err := time.LoadLocation(s)
+	if err == nil {
+		*f.v = (*time.Location)(v)
+	}
+	return err
+}
+
+func (f *timeZoneValue) Get() interface{} { return (*time.Location)(*f.v) }
+
+func (f *timeZoneValue) String() string { return fmt.Sprintf("%v", *f.v) }
+
+// TimeZone parses an IANA time zone name such as "Europe/Paris", "UTC" or "Local".
+func (p *parserMixin) TimeZone() (target **time.Location) {
+	target = new(*time.Location)
+	p.TimeZoneVar(target)
+	return
+}
+
+func (p *parserMixin) TimeZoneVar(target **time.Location) {
+	p.SetValue(newTimeZoneValue(target))
+}
+
+// TimeZones accumulates *time.Location values into a slice.
+func (p *parserMixin) TimeZones() (target *[]*time.Location) {
+	target = new([]*time.Location)
+	p.TimeZonesVar(target)
+	return
+}
+
+func (p *parserMixin) TimeZonesVar(target *[]*time.Location) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newTimeZoneValue(v.(**time.Location))
+	}))
+}
+
+// -- *net.IPNet Value
+type iPNetValue struct{ v **net.IPNet }
+
+func newIPNetValue(p **net.IPNet) *iPNetValue {
+	return &iPNetValue{p}
+}
+
+func (f *iPNetValue) Set(s string) error {
+	v, err := parseIPNet(s)
+	if err == nil {
+		*f.v = (*net.IPNet)(v)
+	}
+	return err
+}
+
+func (f *iPNetValue) Get() interface{} { return (*net.IPNet)(*f.v) }
+
+func (f *iPNetValue) String() string { return fmt.Sprintf("%v", *f.v) }
+
+func (f *iPNetValue) PlaceHolder() string { return "CIDR" }
+
+// IPNet parses an IP network in CIDR notation, eg. 10.0.0.0/8.
+func (p *parserMixin) IPNet() (target **net.IPNet) {
+	target = new(*net.IPNet)
+	p.IPNetVar(target)
+	return
+}
+
+func (p *parserMixin) IPNetVar(target **net.IPNet) {
+	p.SetValue(newIPNetValue(target))
+}
+
+// IPNets accumulates *net.IPNet values into a slice.
+func (p *parserMixin) IPNets() (target *[]*net.IPNet) {
+	target = new([]*net.IPNet)
+	p.IPNetsVar(target)
+	return
+}
+
+func (p *parserMixin) IPNetsVar(target *[]*net.IPNet) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newIPNetValue(v.(**net.IPNet))
+	}))
+}
+
+// -- netip.Prefix Value
+type prefixValue struct{ v *netip.Prefix }
+
+func newPrefixValue(p *netip.Prefix) *prefixValue {
+	return &prefixValue{p}
+}
+
+func (f *prefixValue) Set(s string) error {
+	v, err := netip.ParsePrefix(s)
+	if err == nil {
+		*f.v = (netip.Prefix)(v)
+	}
+	return err
+}
+
+func (f *prefixValue) Get() interface{} { return (netip.Prefix)(*f.v) }
+
+func (f *prefixValue) String() string { return formatValid(*f.v) }
+
+func (f *prefixValue) PlaceHolder() string { return "CIDR" }
+
+// Prefix parses an IP network in CIDR notation, eg. 10.0.0.0/8.
+func (p *parserMixin) Prefix() (target *netip.Prefix) {
+	target = new(netip.Prefix)
+	p.PrefixVar(target)
+	return
+}
+
+func (p *parserMixin) PrefixVar(target *netip.Prefix) {
+	p.SetValue(newPrefixValue(target))
+}
+
+// Prefixes accumulates netip.Prefix values into a slice.
+func (p *parserMixin) Prefixes() (target *[]netip.Prefix) {
+	target = new([]netip.Prefix)
+	p.PrefixesVar(target)
+	return
+}
+
+func (p *parserMixin) PrefixesVar(target *[]netip.Prefix) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newPrefixValue(v.(*netip.Prefix))
+	}))
+}
+
+// -- netip.Addr Value
+type addrValue struct{ v *netip.Addr }
+
+func newAddrValue(p *netip.Addr) *addrValue {
+	return &addrValue{p}
+}
+
+func (f *addrValue) Set(s string) error {
+	v, err := netip.ParseAddr(s)
+	if err == nil {
+		*f.v = (netip.Addr)(v)
+	}
+	return err
+}
+
+func (f *addrValue) Get() interface{} { return (netip.Addr)(*f.v) }
+
+func (f *addrValue) String() string { return formatValid(*f.v) }
+
+func (f *addrValue) PlaceHolder() string { return "IP" }
+
+// Addr parses an IPv4 or IPv6 address.
+func (p *parserMixin) Addr() (target *netip.Addr) {
+	target = new(netip.Addr)
+	p.AddrVar(target)
+	return
+}
+
+func (p *parserMixin) AddrVar(target *netip.Addr) {
+	p.SetValue(newAddrValue(target))
+}
+
+// Addrs accumulates netip.Addr values into a slice.
+func (p *parserMixin) Addrs() (target *[]netip.Addr) {
+	target = new([]netip.Addr)
+	p.AddrsVar(target)
+	return
+}
+
+func (p *parserMixin) AddrsVar(target *[]netip.Addr) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newAddrValue(v.(*netip.Addr))
+	}))
+}
+
+// -- netip.AddrPort Value
+type addrPortValue struct{ v *netip.AddrPort }
+
+func newAddrPortValue(p *netip.AddrPort) *addrPortValue {
+	return &addrPortValue{p}
+}
+
+func (f *addrPortValue) Set(s string) error {
+	v, err := netip.ParseAddrPort(s)
+	if err == nil {
+		*f.v = (netip.AddrPort)(v)
+	}
+	return err
+}
+
+func (f *addrPortValue) Get() interface{} { return (netip.AddrPort)(*f.v) }
+
+func (f *addrPortValue) String() string { return formatValid(*f.v) }
+
+func (f *addrPortValue) PlaceHolder() string { return "IP:PORT" }
+
+// AddrPort parses an IP address and port, eg. [::1]:80.
+func (p *parserMixin) AddrPort() (target *netip.AddrPort) {
+	target = new(netip.AddrPort)
+	p.AddrPortVar(target)
+	return
+}
+
+func (p *parserMixin) AddrPortVar(target *netip.AddrPort) {
+	p.SetValue(newAddrPortValue(target))
+}
+
+// AddrPorts accumulates netip.AddrPort values into a slice.
+func (p *parserMixin) AddrPorts() (target *[]netip.AddrPort) {
+	target = new([]netip.AddrPort)
+	p.AddrPortsVar(target)
+	return
+}
+
+func (p *parserMixin) AddrPortsVar(target *[]netip.AddrPort) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newAddrPortValue(v.(*netip.AddrPort))
+	}))
+}
+
+// -- *net.UDPAddr Value
+type uDPValue struct{ v **net.UDPAddr }
+
+func newUDPValue(p **net.UDPAddr) *uDPValue {
+	return &uDPValue{p}
+}
+
+func (f *uDPValue) Set(s string) error {
+	v, err := net.ResolveUDPAddr("udp", s)
+	if err == nil {
+		*f.v = (*net.UDPAddr)(v)
+	}
+	return err
+}
+
+func (f *uDPValue) Get() interface{} { return (*net.UDPAddr)(*f.v) }
+
+func (f *uDPValue) String() string { return fmt.Sprintf("%v", *f.v) }
+
+func (f *uDPValue) PlaceHolder() string { return "HOST:PORT" }
+
+// UDP (host:port) address.
+func (p *parserMixin) UDP() (target **net.UDPAddr) {
+	target = new(*net.UDPAddr)
+	p.UDPVar(target)
+	return
+}
+
+func (p *parserMixin) UDPVar(target **net.UDPAddr) {
+	p.SetValue(newUDPValue(target))
+}
+
+// UDPList accumulates *net.UDPAddr values into a slice.
+func (p *parserMixin) UDPList() (target *[]*net.UDPAddr) {
+	target = new([]*net.UDPAddr)
+	p.UDPListVar(target)
+	return
+}
+
+func (p *parserMixin) UDPListVar(target *[]*net.UDPAddr) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newUDPValue(v.(**net.UDPAddr))
+	}))
+}
+
+// -- net.HardwareAddr Value
+type hardwareAddrValue struct{ v *net.HardwareAddr }
+
+func newHardwareAddrValue(p *net.HardwareAddr) *hardwareAddrValue {
+	return &hardwareAddrValue{p}
+}
+
+func (f *hardwareAddrValue) Set(s string) error {
+	v, err := net.ParseMAC(s)
+	if err == nil {
+		*f.v = (net.HardwareAddr)(v)
+	}
+	return err
+}
+
+func (f *hardwareAddrValue) Get() interface{} { return (net.HardwareAddr)(*f.v) }
+
+func (f *hardwareAddrValue) String() string { return fmt.Sprintf("%v", *f.v) }
+
+func (f *hardwareAddrValue) PlaceHolder() string { return "MAC" }
+
+// HardwareAddr parses a MAC address, eg. 00:00:5e:00:53:01.
+func (p *parserMixin) HardwareAddr() (target *net.HardwareAddr) {
+	target = new(net.HardwareAddr)
+	p.HardwareAddrVar(target)
+	return
+}
+
+func (p *parserMixin) HardwareAddrVar(target *net.HardwareAddr) {
+	p.SetValue(newHardwareAddrValue(target))
+}
+
+// HardwareAddrs accumulates net.HardwareAddr values into a slice.
+func (p *parserMixin) HardwareAddrs() (target *[]net.HardwareAddr) {
+	target = new([]net.HardwareAddr)
+	p.HardwareAddrsVar(target)
+	return
+}
+
+func (p *parserMixin) HardwareAddrsVar(target *[]net.HardwareAddr) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newHardwareAddrValue(v.(*net.HardwareAddr))
+	}))
+}
+
+// -- uint16 Value
+type portValue struct{ v *uint16 }
+
+func newPortValue(p *uint16) *portValue {
+	return &portValue{p}
+}
+
+func (f *portValue) Set(s string) error {
+	v, err := parsePort(s)
+	if err == nil {
+		*f.v = (uint16)(v)
+	}
+	return err
+}
+
+func (f *portValue) Get() interface{} { return (uint16)(*f.v) }
+
+func (f *portValue) String() string { return fmt.Sprintf("%v", *f.v) }
+
+func (f *portValue) PlaceHolder() string { return "PORT" }
+
+// Port parses a port number or service name.
+func (p *parserMixin) Port() (target *uint16) {
+	target = new(uint16)
+	p.PortVar(target)
+	return
+}
+
+func (p *parserMixin) PortVar(target *uint16) {
+	p.SetValue(newPortValue(target))
+}
+
+// Ports accumulates uint16 values into a slice.
+func (p *parserMixin) Ports() (target *[]uint16) {
+	target = new([]uint16)
+	p.PortsVar(target)
+	return
+}
+
+func (p *parserMixin) PortsVar(target *[]uint16) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newPortValue(v.(*uint16))
+	}))
+}
+
+// -- PortRange Value
+type portRangeValue struct{ v *PortRange }
+
+func newPortRangeValue(p *PortRange) *portRangeValue {
+	return &portRangeValue{p}
+}
+
+func (f *portRangeValue) Set(s string) error {
+	v, err := parsePortRange(s)
+	if err == nil {
+		*f.v = (PortRange)(v)
+	}
+	return err
+}
+
+func (f *portRangeValue) Get() interface{} { return (PortRange)(*f.v) }
+
+func (f *portRangeValue) String() string { return f.v.String() }
+
+func (f *portRangeValue) PlaceHolder() string { return "PORT[-PORT]" }
+
+// PortRange parses a port or an inclusive range of ports, eg. 8000-8080.
+func (p *parserMixin) PortRange() (target *PortRange) {
+	target = new(PortRange)
+	p.PortRangeVar(target)
+	return
+}
+
+func (p *parserMixin) PortRangeVar(target *PortRange) {
+	p.SetValue(newPortRangeValue(target))
+}
+
+// PortRanges accumulates PortRange values into a slice.
+func (p *parserMixin) PortRanges() (target *[]PortRange) {
+	target = new([]PortRange)
+	p.PortRangesVar(target)
+	return
+}
+
+func (p *parserMixin) PortRangesVar(target *[]PortRange) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newPortRangeValue(v.(*PortRange))
+	}))
+}
+
+// HostPorts accumulates string values into a slice.
+func (p *parserMixin) HostPorts(defaultPort uint16) (target *[]string) {
+	target = new([]string)
+	p.HostPortsVar(target, defaultPort)
+	return
+}
+
+func (p *parserMixin) HostPortsVar(target *[]string, defaultPort uint16) {
+	p.SetValue(newAccumulator(target, func(v interface{}) Value {
+		return newHostPortValue(v.(*string), defaultPort)
+	}))
+}
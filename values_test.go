@@ -1,8 +1,13 @@
 package kingpin
 
 import (
+	"fmt"
 	"net"
+	"net/netip"
+	"strings"
+	"time"
 
+	"github.com/alecthomas/units"
 	"github.com/stretchr/testify/assert"
 
 	"testing"
@@ -47,6 +52,52 @@ func TestEnumVar(t *testing.T) {
 	assert.Equal(t, "one", a)
 }
 
+func TestEnumError(t *testing.T) {
+	app := newTestApp()
+	app.Arg("a", "").Enum("one", "two")
+	_, err := app.Parse([]string{"three"})
+	assert.EqualError(t, err, "enum value must be one of one,two, got 'three'")
+}
+
+func TestEnumIgnoreCase(t *testing.T) {
+	app := newTestApp()
+	format := app.Flag("format", "").IgnoreCase().Enum("json", "text")
+	levels := app.Arg("level", "").IgnoreCase().Enums("debug", "info")
+	_, err := app.Parse([]string{"--format=JSON", "Debug", "INFO"})
+	assert.NoError(t, err)
+	assert.Equal(t, "json", *format)
+	assert.Equal(t, []string{"debug", "info"}, *levels)
+
+	app = newTestApp()
+	app.Flag("name", "").IgnoreCase().String()
+	_, err = app.Parse([]string{})
+	assert.EqualError(t, err, "IgnoreCase() for '--name', which is not an enum")
+}
+
+func TestEnumDescribed(t *testing.T) {
+	app := newTestApp()
+	format := app.Flag("format", "").EnumDescribed(map[string]string{"text": "Plain text.", "json": "JSON."})
+	formats := app.Arg("formats", "").EnumsDescribed(map[string]string{"text": "Text report.", "json": "JSON report."})
+	_, err := app.Parse([]string{"--format=json", "text", "json"})
+	assert.NoError(t, err)
+	assert.Equal(t, "json", *format)
+	assert.Equal(t, []string{"text", "json"}, *formats)
+
+	_, err = app.Parse([]string{"--format=yaml"})
+	assert.EqualError(t, err, "enum value must be one of json,text, got 'yaml'")
+	assert.Equal(t, []string{"json", "text"}, app.GetFlag("format").resolveCompletions())
+
+	complete := func(args ...string) []string {
+		context, err := app.ParseContext(append([]string{"--completion-descriptions"}, args...))
+		assert.NoError(t, err)
+		options, completing := app.completionCandidates(context)
+		assert.NotNil(t, completing)
+		return completing.describeCompletions(options)
+	}
+	assert.Equal(t, []string{"json\tJSON.", "text\tPlain text."}, complete("--format", ""))
+	assert.Equal(t, []string{"json\tJSON report.", "text\tText report."}, complete(""))
+}
+
 func TestCounter(t *testing.T) {
 	app := New("", "")
 	c := app.Flag("f", "").Counter()
@@ -96,3 +147,271 @@ func TestSetValueDoesNotReset(t *testing.T) {
 	app.Flag("set", "").StringMapVar(&mapping)
 	assert.NotEmpty(t, mapping)
 }
+
+func TestTimeValue(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 30, 0, 0, time.Local)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"2024-01-02 03:04", time.Date(2024, 1, 2, 3, 4, 0, 0, time.Local)},
+		{"2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+		{"now", now},
+		{"now-1h", now.Add(-time.Hour)},
+		{"-2d", now.Add(-48 * time.Hour)},
+		{"yesterday", time.Date(2024, 3, 14, 0, 0, 0, 0, time.Local)},
+		{"tomorrow+2h", time.Date(2024, 3, 16, 2, 0, 0, 0, time.Local)},
+	}
+	for _, test := range tests {
+		var actual time.Time
+		assert.NoError(t, newTimeValue(&actual).Set(test.input), test.input)
+		assert.True(t, test.expected.Equal(actual), "%s: %s != %s", test.input, test.expected, actual)
+	}
+
+	var actual time.Time
+	assert.NoError(t, newTimeValue(&actual, "02/01/2006").Set("25/12/2023"))
+	assert.Equal(t, time.Date(2023, 12, 25, 0, 0, 0, 0, time.Local), actual)
+	assert.EqualError(t, newTimeValue(&actual, "02/01/2006").Set("2023-12-25"), "invalid time '2023-12-25', expected 02/01/2006 or a relative time such as now-1h")
+	assert.Error(t, newTimeValue(&actual).Set("now-soon"))
+}
+
+func TestDateValue(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 30, 0, 0, time.Local)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	app := newTestApp()
+	since := app.Flag("since", "").Date()
+	days := app.Flag("day", "").Dates()
+	_, err := app.Parse([]string{"--since=now-7d", "--day=2024-01-01", "--day=today"})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 8, 0, 0, 0, 0, time.Local), *since)
+	assert.Equal(t, []time.Time{time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)}, *days)
+	assert.Equal(t, "2024-03-08", app.GetFlag("since").value.String())
+	assert.Equal(t, "YYYY-MM-DD", app.GetFlag("since").Model().FormatPlaceHolder())
+}
+
+func TestUnixTimeValue(t *testing.T) {
+	app := newTestApp()
+	at := app.Flag("at", "").UnixTime()
+	_, err := app.Parse([]string{"--at=1700000000.25"})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Unix(1700000000, 250000000), *at)
+	assert.Equal(t, "1700000000", app.GetFlag("at").value.String())
+	_, err = app.Parse([]string{"--at=soon"})
+	assert.Error(t, err)
+}
+
+func TestTimeZoneValue(t *testing.T) {
+	app := newTestApp()
+	zones := app.Flag("tz", "").TimeZones()
+	_, err := app.Parse([]string{"--tz=UTC", "--tz=Local"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*time.Location{time.UTC, time.Local}, *zones)
+	_, err = app.Parse([]string{"--tz=Nowhere/Special"})
+	assert.Error(t, err)
+}
+
+func TestNetworkValues(t *testing.T) {
+	app := newTestApp()
+	ipNet := app.Flag("net", "").IPNet()
+	prefixes := app.Flag("prefix", "").Prefixes()
+	addr := app.Flag("addr", "").Addr()
+	addrPort := app.Flag("addr-port", "").AddrPort()
+	udp := app.Flag("udp", "").UDP()
+	mac := app.Flag("mac", "").HardwareAddr()
+	ports := app.Flag("port", "").Ports()
+	ranges := app.Flag("range", "").PortRanges()
+	_, err := app.Parse([]string{
+		"--net=10.1.2.3/8", "--prefix=192.168.0.0/16", "--prefix=fd00::/8",
+		"--addr=::1", "--addr-port=[::1]:80", "--udp=127.0.0.1:53",
+		"--mac=00:00:5e:00:53:01", "--port=8080", "--port=http",
+		"--range=8000-8080", "--range=22",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", (*ipNet).String())
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("192.168.0.0/16"), netip.MustParsePrefix("fd00::/8")}, *prefixes)
+	assert.Equal(t, netip.IPv6Loopback(), *addr)
+	assert.Equal(t, netip.MustParseAddrPort("[::1]:80"), *addrPort)
+	assert.Equal(t, 53, (*udp).Port)
+	assert.Equal(t, "00:00:5e:00:53:01", mac.String())
+	assert.Equal(t, []uint16{8080, 80}, *ports)
+	assert.Equal(t, []PortRange{{8000, 8080}, {22, 22}}, *ranges)
+	assert.Equal(t, "8000-8080,22", app.GetFlag("range").value.String())
+	assert.Equal(t, "CIDR", app.GetFlag("prefix").Model().FormatPlaceHolder())
+
+	for arg, expected := range map[string]string{
+		"--port=0":           "invalid port '0': must be 1-65535",
+		"--port=65536":       "invalid port '65536': must be 1-65535",
+		"--port=-1":          "invalid port '-1'",
+		"--range=8080-8000":  "invalid port range '8080-8000', 8000 is less than 8080",
+		"--prefix=10.0.0.1":  `netip.ParsePrefix("10.0.0.1"): no '/'`,
+		"--mac=nope":         "address nope: invalid MAC address",
+		"--single=8080-8000": "invalid port range '8080-8000', 8000 is less than 8080",
+	} {
+		app := newTestApp()
+		app.Flag("port", "").Ports()
+		app.Flag("range", "").PortRanges()
+		app.Flag("single", "").PortRange()
+		app.Flag("prefix", "").Prefixes()
+		app.Flag("mac", "").HardwareAddr()
+		_, err = app.Parse([]string{arg})
+		assert.EqualError(t, err, expected, arg)
+	}
+}
+
+func TestHostPortValue(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"example.com", "example.com:443"},
+		{"example.com:8443", "example.com:8443"},
+		{"::1", "[::1]:443"},
+		{"[::1]", "[::1]:443"},
+		{"[::1]:80", "[::1]:80"},
+	}
+	for _, test := range tests {
+		var actual string
+		assert.NoError(t, newHostPortValue(&actual, 443).Set(test.input), test.input)
+		assert.Equal(t, test.expected, actual)
+	}
+	var actual string
+	assert.EqualError(t, newHostPortValue(&actual, 443).Set(":80"), "invalid address ':80': missing host")
+	assert.Error(t, newHostPortValue(&actual, 443).Set("example.com:0"))
+	assert.Error(t, newHostPortValue(&actual, 443).Set("a:b:c"))
+
+	app := newTestApp()
+	hosts := app.Flag("host", "").HostPorts(80)
+	_, err := app.Parse([]string{"--host=a", "--host=b:81"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a:80", "b:81"}, *hosts)
+	assert.Equal(t, "HOST[:PORT]", app.GetFlag("host").Model().FormatPlaceHolder())
+}
+
+func TestIntValueIsStrict(t *testing.T) {
+	var n int
+	v := newIntValue(&n)
+	assert.NoError(t, v.Set("1_000"))
+	assert.Equal(t, 1000, n)
+	assert.NoError(t, v.Set("0x10"))
+	assert.Equal(t, 16, n)
+	assert.EqualError(t, v.Set("1.9"), `strconv.ParseInt: parsing "1.9": invalid syntax`)
+	assert.EqualError(t, v.Set("99999999999999999999"), `strconv.ParseInt: parsing "99999999999999999999": value out of range`)
+	assert.Equal(t, 16, n)
+}
+
+func TestIntValueLeadingZeros(t *testing.T) {
+	var n int
+	v := newIntValue(&n)
+	for input, expected := range map[string]int{
+		"010": 10, "08": 8, "-007": -7, "000": 0, "0": 0, "0o10": 8, "0b101": 5, "0X1f": 31,
+	} {
+		assert.NoError(t, v.Set(input), input)
+		assert.Equal(t, expected, n, input)
+	}
+	assert.EqualError(t, v.Set("0_8"), `strconv.ParseInt: parsing "0_8": invalid syntax`)
+
+	var u uint8
+	assert.NoError(t, newUint8Value(&u).Set("010"))
+	assert.Equal(t, uint8(10), u)
+	assert.EqualError(t, newUint8Value(&u).Set("0256"), `strconv.ParseUint: parsing "0256": value out of range`)
+
+	app := newTestApp()
+	m := app.Flag("m", "").IntMap()
+	_, err := app.Parse([]string{"--m=a=010"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 10}, *m)
+}
+
+func TestQuantityValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"42", 42},
+		{"1_000_000", 1000000},
+		{"1k", 1000},
+		{"2M", 2000000},
+		{"1.5G", 1500000000},
+		{"1Ki", 1024},
+		{"-3T", -3000000000000},
+	}
+	for _, test := range tests {
+		actual, err := parseQuantity(test.input)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, actual, test.input)
+	}
+	for input, expected := range map[string]string{
+		"1.5":   "invalid quantity '1.5', not a whole number",
+		"1.5x":  "invalid quantity '1.5x'",
+		"10E":   "invalid quantity '10E', value out of range",
+		"1.1k1": "invalid quantity '1.1k1'",
+	} {
+		_, err := parseQuantity(input)
+		assert.EqualError(t, err, expected, input)
+	}
+}
+
+func TestMetricBytesValue(t *testing.T) {
+	app := newTestApp()
+	size := app.Flag("size", "").MetricBytes()
+	_, err := app.Parse([]string{"--size=1.5MB"})
+	assert.NoError(t, err)
+	assert.Equal(t, units.MetricBytes(1500000), *size)
+}
+
+// A Value that upper cases its input.
+type upperValue string
+
+func (u *upperValue) Set(value string) error {
+	if value == "" {
+		return fmt.Errorf("empty value")
+	}
+	*u = upperValue(strings.ToUpper(value))
+	return nil
+}
+
+func (u *upperValue) Get() interface{} { return string(*u) }
+
+func (u *upperValue) String() string { return string(*u) }
+
+// An encoding.TextUnmarshaler counting the characters of its input.
+type lengthText int
+
+func (l *lengthText) UnmarshalText(text []byte) error {
+	*l = lengthText(len(text))
+	return nil
+}
+
+func TestAccumulate(t *testing.T) {
+	app := newTestApp()
+	var uppers []upperValue
+	var lengths []lengthText
+	app.Flag("upper", "").SetValue(Accumulate(&uppers))
+	app.Flag("length", "").SetValue(Accumulate(&lengths))
+	_, err := app.Parse([]string{"--upper=a", "--upper=b", "--length=abc", "--length=de"})
+	assert.NoError(t, err)
+	assert.Equal(t, []upperValue{"A", "B"}, uppers)
+	assert.Equal(t, []lengthText{3, 2}, lengths)
+	assert.Same(t, &uppers, app.GetFlag("upper").value.(Getter).Get())
+	assert.Equal(t, "--upper=UPPER ...", formatFlag(false, app.GetFlag("upper").Model()))
+
+	_, err = app.Parse([]string{"--upper="})
+	assert.EqualError(t, err, "empty value")
+	assert.Panics(t, func() { Accumulate(&[]chan int{}) })
+}
+
+func TestRepeatable(t *testing.T) {
+	app := newTestApp()
+	values := app.Flag("upper", "").Repeatable(func() Value { return new(upperValue) })
+	_, err := app.Parse([]string{"--upper=a", "--upper=b"})
+	assert.NoError(t, err)
+	assert.Len(t, *values, 2)
+	assert.Equal(t, "A,B", app.GetFlag("upper").value.String())
+	assert.Equal(t, []interface{}{"A", "B"}, app.GetFlag("upper").value.(Getter).Get())
+	assert.Equal(t, "--upper=UPPER ...", formatFlag(false, app.GetFlag("upper").Model()))
+}
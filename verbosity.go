@@ -0,0 +1,107 @@
+package kingpin
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Verbosity adds -v/--verbose and -q/--quiet flags, which increase and
+// decrease a verbosity level, and a --verbosity=N flag, which sets it. Short
+// flags may be combined, eg. -vvv, and flags apply in the order given. The
+// level starts at 0 and is clamped between min and max once the flags have
+// been applied, so -vvvvv -q with a max of 3 is 3.
+//
+// The flags may be customised with GetFlag(), eg. GetFlag("quiet").Short('s').
+func (f *flagGroup) Verbosity(min, max int) (target *int) {
+	target = new(int)
+	f.VerbosityVar(target, min, max)
+	return
+}
+
+// VerbosityVar is like Verbosity but stores the level in target, starting
+// from its current value, which is also clamped between min and max.
+func (f *flagGroup) VerbosityVar(target *int, min, max int) {
+	f.verbosity(&verbosity{target: target, min: min, max: max})
+}
+
+func (f *flagGroup) verbosity(v *verbosity) {
+	f.Flag("verbose", "Increase verbosity.").Short('v').PreAction(v.clamp).SetValue(&verbosityStep{v, 1})
+	f.Flag("quiet", "Decrease verbosity.").Short('q').PreAction(v.clamp).SetValue(&verbosityStep{v, -1})
+	// The default clamps the starting level, even if no flags are given.
+	f.Flag("verbosity", fmt.Sprintf("Set the verbosity level, from %d to %d.", v.min, v.max)).PlaceHolder("N").
+		DefaultFunc(func() (string, error) { return strconv.Itoa(v.bounded(*v.target)), nil }).
+		SetValue(&verbosityLevel{v})
+}
+
+// A verbosity level shared by the verbosity flags.
+type verbosity struct {
+	target   *int
+	min, max int
+	onChange func(level int)
+}
+
+func (v *verbosity) set(level int) {
+	*v.target = level
+	if v.onChange != nil {
+		v.onChange(level)
+	}
+}
+
+// Level between min and max.
+func (v *verbosity) bounded(level int) int {
+	if level < v.min {
+		return v.min
+	} else if level > v.max {
+		return v.max
+	}
+	return level
+}
+
+// Clamp the level once the verbose and quiet flags have been applied.
+func (v *verbosity) clamp(*ParseContext) error {
+	v.set(v.bounded(*v.target))
+	return nil
+}
+
+func (v *verbosity) Get() interface{} { return *v.target }
+
+func (v *verbosity) String() string { return strconv.Itoa(*v.target) }
+
+// Changes the verbosity level by step each time the flag is given, leaving it
+// to be clamped after parsing.
+type verbosityStep struct {
+	*verbosity
+	step int
+}
+
+func (s *verbosityStep) Set(value string) error {
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		*s.target += s.step
+	}
+	return nil
+}
+
+func (s *verbosityStep) IsBoolFlag() bool { return true }
+
+func (s *verbosityStep) IsCumulative() bool { return true }
+
+// Sets the verbosity level.
+type verbosityLevel struct {
+	*verbosity
+}
+
+func (l *verbosityLevel) Set(value string) error {
+	level, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if level < l.min || level > l.max {
+		return fmt.Errorf("verbosity must be from %d to %d, got %d", l.min, l.max, level)
+	}
+	l.set(level)
+	return nil
+}
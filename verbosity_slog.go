@@ -0,0 +1,30 @@
+//go:build go1.21
+
+package kingpin
+
+import "log/slog"
+
+// SlogLevel maps a verbosity level to a slog.Level, where 0 is
+// slog.LevelInfo, 1 is slog.LevelDebug, -1 is slog.LevelWarn and -2 is
+// slog.LevelError.
+func SlogLevel(verbosity int) slog.Level {
+	return slog.LevelInfo - slog.Level(4*verbosity)
+}
+
+// SlogVerbosity is like Verbosity but sets level to the slog.Level of the
+// verbosity, from slog.LevelError to slog.LevelDebug. The verbosity starts at
+// the current level.
+//
+//	level := new(slog.LevelVar)
+//	app.SlogVerbosity(level)
+//	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+func (f *flagGroup) SlogVerbosity(level *slog.LevelVar) {
+	target := new(int)
+	*target = int(slog.LevelInfo-level.Level()) / 4
+	f.verbosity(&verbosity{
+		target:   target,
+		min:      -2,
+		max:      1,
+		onChange: func(verbosity int) { level.Set(SlogLevel(verbosity)) },
+	})
+}
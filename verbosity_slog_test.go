@@ -0,0 +1,24 @@
+//go:build go1.21
+
+package kingpin
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogVerbosity(t *testing.T) {
+	app := newTestApp()
+	level := new(slog.LevelVar)
+	app.SlogVerbosity(level)
+	_, err := app.Parse([]string{"-vv"})
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, level.Level())
+
+	_, err = app.Parse([]string{"-qqq"})
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelError, level.Level())
+	assert.Equal(t, slog.LevelWarn, SlogLevel(-1))
+}
@@ -0,0 +1,63 @@
+package kingpin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerbosity(t *testing.T) {
+	app := newTestApp()
+	level := app.Verbosity(-1, 3)
+	_, err := app.Parse([]string{"-vvv"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *level)
+
+	*level = 0
+	_, err = app.Parse([]string{"-vvvvv", "-q"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *level)
+
+	*level = 0
+	_, err = app.Parse([]string{"-qqq"})
+	assert.NoError(t, err)
+	assert.Equal(t, -1, *level)
+
+	*level = 0
+	_, err = app.Parse([]string{"--verbosity=2", "--quiet"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *level)
+
+	_, err = app.Parse([]string{"--verbosity=4"})
+	assert.EqualError(t, err, "verbosity must be from -1 to 3, got 4")
+	assert.Equal(t, "-v, --[no-]verbose ...", formatFlag(true, app.GetFlag("verbose").Model()))
+	assert.Equal(t, "    --verbosity=N", formatFlag(true, app.GetFlag("verbosity").Model()))
+}
+
+func TestVerbosityCommand(t *testing.T) {
+	app := newTestApp()
+	level := 1
+	app.Command("run", "").VerbosityVar(&level, 0, 2)
+	_, err := app.Parse([]string{"run", "-v"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, level)
+}
+
+func TestVerbosityClampsStart(t *testing.T) {
+	app := newTestApp()
+	level := 7
+	app.VerbosityVar(&level, 0, 3)
+	_, err := app.Parse([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, level)
+
+	level = 7
+	_, err = app.Parse([]string{"-q"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, level)
+
+	level = -5
+	_, err = app.Parse([]string{"-v"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, level)
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || freebsd || darwin || dragonfly || netbsd || openbsd)
+// +build !linux,!freebsd,!darwin,!dragonfly,!netbsd,!openbsd
+
+package kingpin
+
+import "os"
+
+// Whether the current user can create files in dir, judged by its permission
+// bits only.
+func isWritableDir(dir string) bool {
+	s, err := os.Stat(dir)
+	return err == nil && s.Mode().Perm()&0222 != 0
+}
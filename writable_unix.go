@@ -0,0 +1,14 @@
+//go:build linux || freebsd || darwin || dragonfly || netbsd || openbsd
+// +build linux freebsd darwin dragonfly netbsd openbsd
+
+package kingpin
+
+import "syscall"
+
+// W_OK from unistd.h, which is not defined by syscall on all platforms.
+const accessWriteOK = 0x2
+
+// Whether the current user can create files in dir.
+func isWritableDir(dir string) bool {
+	return syscall.Access(dir, accessWriteOK) == nil
+}